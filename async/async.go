@@ -0,0 +1,140 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Future is the result of an asynchronous call.
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Async calls fn in a new goroutine and returns its future result. A panic
+// of fn is recovered into the error of the future.
+func Async[T any](fn func() (T, error)) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		defer func() {
+			if v := recover(); v != nil {
+				f.err = fmt.Errorf("async: panic: %v", v)
+			}
+		}()
+		f.value, f.err = fn()
+	}()
+	return f
+}
+
+// Await waits for the result until ctx is done. The call goes on when ctx
+// is done first.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done returns a channel closed once the result is available.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Catch returns a future recovering from the error of f with fn, whose
+// results replace it. Successful results pass through.
+func (f *Future[T]) Catch(fn func(err error) (T, error)) *Future[T] {
+	return Async(func() (T, error) {
+		<-f.done
+		if f.err != nil {
+			return fn(f.err)
+		}
+		return f.value, nil
+	})
+}
+
+// Then returns a future of fn applied to the result of f. Errors pass
+// through without calling fn.
+func Then[T, U any](f *Future[T], fn func(v T) (U, error)) *Future[U] {
+	return Async(func() (U, error) {
+		<-f.done
+		if f.err != nil {
+			var zero U
+			return zero, f.err
+		}
+		return fn(f.value)
+	})
+}
+
+// All returns a future of the results of fs in order, failing with the
+// first error as soon as it occurs.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	return Async(func() ([]T, error) {
+		values := make([]T, len(fs))
+		errs := make(chan error, len(fs))
+		for i, f := range fs {
+			go func() {
+				<-f.done
+				values[i] = f.value
+				errs <- f.err
+			}()
+		}
+		for range fs {
+			if err := <-errs; err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	})
+}
+
+// Any returns a future of the first successful result of fs, failing with
+// all the errors joined if all of them fail.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	return Async(func() (T, error) {
+		results := race(fs)
+		errs := make([]error, 0, len(fs))
+		for range fs {
+			f := <-results
+			if f.err == nil {
+				return f.value, nil
+			}
+			errs = append(errs, f.err)
+		}
+		var zero T
+		if len(errs) == 0 {
+			return zero, errors.New("async: no futures")
+		}
+		return zero, errors.Join(errs...)
+	})
+}
+
+// Race returns a future of the result of the first of fs to complete,
+// successfully or not.
+func Race[T any](fs ...*Future[T]) *Future[T] {
+	return Async(func() (T, error) {
+		if len(fs) == 0 {
+			var zero T
+			return zero, errors.New("async: no futures")
+		}
+		f := <-race(fs)
+		return f.value, f.err
+	})
+}
+
+// race returns a channel receiving fs in order of completion.
+func race[T any](fs []*Future[T]) <-chan *Future[T] {
+	ch := make(chan *Future[T], len(fs))
+	for _, f := range fs {
+		go func() {
+			<-f.done
+			ch <- f
+		}()
+	}
+	return ch
+}
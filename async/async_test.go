@@ -0,0 +1,78 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAwait(t *testing.T) {
+	f := Async(func() (int, error) { return 1, nil })
+	if v, err := f.Await(context.Background()); err != nil || v != 1 {
+		t.Fatalf("expected %v, got %v, %v", 1, v, err)
+	}
+	slow := Async(func() (int, error) {
+		time.Sleep(time.Second)
+		return 1, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := slow.Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	p := Async(func() (int, error) { panic("boom") })
+	if _, err := p.Await(context.Background()); err == nil {
+		t.Fatalf("expected the panic to be recovered into an error")
+	}
+}
+
+func TestThenCatch(t *testing.T) {
+	errFailed := errors.New("failed")
+	s := Then(Async(func() (int, error) { return 2, nil }), func(v int) (string, error) {
+		return strconv.Itoa(v * 2), nil
+	})
+	if v, err := s.Await(context.Background()); err != nil || v != "4" {
+		t.Fatalf("expected %v, got %v, %v", "4", v, err)
+	}
+	f := Then(Async(func() (int, error) { return 0, errFailed }), func(v int) (int, error) {
+		t.Fatalf("expected fn not to be called on error")
+		return v, nil
+	}).Catch(func(err error) (int, error) {
+		if !errors.Is(err, errFailed) {
+			t.Errorf("expected %v, got %v", errFailed, err)
+		}
+		return -1, nil
+	})
+	if v, err := f.Await(context.Background()); err != nil || v != -1 {
+		t.Fatalf("expected %v, got %v, %v", -1, v, err)
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	after := func(d time.Duration, v int, err error) *Future[int] {
+		return Async(func() (int, error) {
+			time.Sleep(d)
+			return v, err
+		})
+	}
+	if vs, err := All(after(20*time.Millisecond, 1, nil), after(0, 2, nil)).Await(ctx); err != nil || vs[0] != 1 || vs[1] != 2 {
+		t.Fatalf("expected %v, got %v, %v", []int{1, 2}, vs, err)
+	}
+	start := time.Now()
+	if _, err := All(after(time.Second, 1, nil), after(0, 0, errFailed)).Await(ctx); !errors.Is(err, errFailed) || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected All to fail fast with %v, got %v", errFailed, err)
+	}
+	if v, err := Any(after(0, 0, errFailed), after(10*time.Millisecond, 2, nil)).Await(ctx); err != nil || v != 2 {
+		t.Fatalf("expected %v, got %v, %v", 2, v, err)
+	}
+	if _, err := Any(after(0, 0, errFailed), after(0, 0, errFailed)).Await(ctx); !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+	if _, err := Race(after(50*time.Millisecond, 1, nil), after(0, 0, errFailed)).Await(ctx); !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+}
@@ -0,0 +1,195 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when adding items to a closed Batcher.
+var ErrClosed = errors.New("batch: batcher closed")
+
+// Errors holds the errors of the items of a batch by index, nil for the items
+// that succeeded. A flush function returning Errors fails its items one by one.
+type Errors []error
+
+func (e Errors) Error() string {
+	n := 0
+	for _, err := range e {
+		if err != nil {
+			n++
+		}
+	}
+	return fmt.Sprintf("batch: %d of %d items failed", n, len(e))
+}
+
+// Option is batcher option.
+type Option func(*options)
+
+type options struct {
+	maxSize int
+	maxWait time.Duration
+}
+
+// WithMaxSize sets the number of items flushed at once, 100 by default.
+func WithMaxSize(n int) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// WithMaxWait sets how long the first item of a batch waits for others
+// before the batch is flushed, 10ms by default.
+func WithMaxWait(d time.Duration) Option {
+	return func(o *options) {
+		o.maxWait = d
+	}
+}
+
+// Batcher groups the items added by concurrent callers into batches flushed
+// together, e.g. to write them with a single call to a downstream API, and
+// returns the result of each item to its caller.
+type Batcher[T, R any] struct {
+	flush func(ctx context.Context, items []T) ([]R, error)
+	opts  options
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	pending []*request[T, R]
+	timer   *time.Timer
+	closed  bool
+}
+
+type request[T, R any] struct {
+	item  T
+	done  chan struct{}
+	value R
+	err   error
+}
+
+// New creates a Batcher flushing batches with flush, which returns the
+// results of the items in order. It fails the items one by one by returning
+// Errors, or all of them by returning any other error.
+func New[T, R any](flush func(ctx context.Context, items []T) ([]R, error), opts ...Option) *Batcher[T, R] {
+	o := options{maxSize: 100, maxWait: 10 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.maxSize = max(o.maxSize, 1)
+	return &Batcher[T, R]{flush: flush, opts: o}
+}
+
+// Do adds item to the next batch and waits for its result until ctx is done.
+// The item is flushed even if ctx is done first.
+func (b *Batcher[T, R]) Do(ctx context.Context, item T) (R, error) {
+	r := &request[T, R]{item: item, done: make(chan struct{})}
+	if err := b.add(r); err != nil {
+		var zero R
+		return zero, err
+	}
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero R
+		return zero, ctx.Err()
+	}
+}
+
+// Close flushes the pending items and waits for the flushes in flight until
+// ctx is done. Items added afterwards fail with ErrClosed.
+func (b *Batcher[T, R]) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.dispatch()
+	b.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Batcher[T, R]) add(r *request[T, R]) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	b.pending = append(b.pending, r)
+	switch {
+	case len(b.pending) >= b.opts.maxSize:
+		b.dispatch()
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.opts.maxWait, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if len(b.pending) > 0 && b.pending[0] == r {
+				b.dispatch()
+			}
+		})
+	}
+	return nil
+}
+
+// dispatch flushes the pending items in the background.
+func (b *Batcher[T, R]) dispatch() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+	reqs := b.pending
+	b.pending = nil
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.run(reqs)
+	}()
+}
+
+// call flushes items, reporting a panic of the flush function as an error.
+func (b *Batcher[T, R]) call(items []T) (values []R, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			values, err = nil, fmt.Errorf("batch: panic: %v", v)
+		}
+	}()
+	return b.flush(context.Background(), items)
+}
+
+// run flushes reqs and delivers the results to their callers.
+func (b *Batcher[T, R]) run(reqs []*request[T, R]) {
+	items := make([]T, len(reqs))
+	for i, r := range reqs {
+		items[i] = r.item
+	}
+	values, err := b.call(items)
+	var errs Errors
+	if errors.As(err, &errs) && len(errs) != len(reqs) {
+		errs = nil
+	}
+	if err == nil && len(values) != len(reqs) {
+		err = fmt.Errorf("batch: flush returned %d results for %d items", len(values), len(reqs))
+	}
+	for i, r := range reqs {
+		r.err = err
+		if errs != nil {
+			r.err = errs[i]
+		}
+		if r.err == nil && i < len(values) {
+			r.value = values[i]
+		}
+		close(r.done)
+	}
+}
@@ -0,0 +1,131 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatcherMaxSize(t *testing.T) {
+	var flushes atomic.Int32
+	b := New(func(ctx context.Context, items []int) ([]int, error) {
+		flushes.Add(1)
+		if len(items) != 10 {
+			t.Errorf("expected batches of %v, got %v", 10, len(items))
+		}
+		out := make([]int, len(items))
+		for i, v := range items {
+			out[i] = v * 2
+		}
+		return out, nil
+	}, WithMaxSize(10), WithMaxWait(time.Hour))
+	var wg sync.WaitGroup
+	for i := range 30 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := b.Do(context.Background(), i); err != nil || v != i*2 {
+				t.Errorf("expected %v, got %v, %v", i*2, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if flushes.Load() != 3 {
+		t.Fatalf("expected %v flushes, got %v", 3, flushes.Load())
+	}
+}
+
+func TestBatcherMaxWait(t *testing.T) {
+	b := New(func(ctx context.Context, items []string) ([]int, error) {
+		out := make([]int, len(items))
+		for i, s := range items {
+			out[i] = len(s)
+		}
+		return out, nil
+	}, WithMaxWait(10*time.Millisecond))
+	start := time.Now()
+	if v, err := b.Do(context.Background(), "abc"); err != nil || v != 3 {
+		t.Fatalf("expected %v, got %v, %v", 3, v, err)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Fatalf("expected the batch to wait for more items, got %v", d)
+	}
+}
+
+func TestBatcherErrors(t *testing.T) {
+	errOdd, errAll := errors.New("odd"), errors.New("all")
+	fail := errOdd
+	b := New(func(ctx context.Context, items []int) ([]int, error) {
+		if fail == errAll {
+			return nil, errAll
+		}
+		errs := make(Errors, len(items))
+		for i, v := range items {
+			if v%2 == 1 {
+				errs[i] = errOdd
+			}
+		}
+		return items, errs
+	}, WithMaxSize(2), WithMaxWait(time.Hour))
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := b.Do(context.Background(), i)
+			if i == 1 && !errors.Is(err, errOdd) || i == 0 && (err != nil || v != 0) {
+				t.Errorf("unexpected result of %v: %v, %v", i, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	fail = errAll
+	go b.Do(context.Background(), 0)
+	if _, err := b.Do(context.Background(), 2); !errors.Is(err, errAll) {
+		t.Fatalf("expected %v, got %v", errAll, err)
+	}
+}
+
+func TestBatcherPanic(t *testing.T) {
+	b := New(func(ctx context.Context, items []int) ([]int, error) {
+		panic("boom")
+	}, WithMaxSize(2), WithMaxWait(time.Hour))
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := b.Do(context.Background(), i); err == nil || !strings.Contains(err.Error(), "boom") {
+				t.Errorf("expected panic error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBatcherClose(t *testing.T) {
+	var flushed atomic.Int32
+	b := New(func(ctx context.Context, items []int) ([]int, error) {
+		flushed.Add(int32(len(items)))
+		return items, nil
+	}, WithMaxWait(time.Hour))
+	done := make(chan error)
+	go func() {
+		_, err := b.Do(context.Background(), 1)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := <-done; err != nil || flushed.Load() != 1 {
+		t.Fatalf("expected the pending item to be flushed, got %v", err)
+	}
+	if _, err := b.Do(context.Background(), 2); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected %v, got %v", ErrClosed, err)
+	}
+}
@@ -0,0 +1,88 @@
+package breaker
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNotAllowed is returned by Allow when the breaker rejects a request.
+var ErrNotAllowed = errors.New("breaker: not allowed")
+
+// Breaker protects a dependency by rejecting requests while it is failing.
+type Breaker interface {
+	// Allow reports with ErrNotAllowed that a request must not be sent.
+	Allow() error
+	// MarkSuccess records a successful request.
+	MarkSuccess()
+	// MarkFailed records a failed request.
+	MarkFailed()
+	// Stats returns a snapshot of the statistics of the breaker.
+	Stats() Stats
+}
+
+// Stats is a snapshot of the statistics of a breaker. The counts are
+// cumulative since the breaker was created.
+type Stats struct {
+	State     State
+	Successes int64
+	Failures  int64
+	Rejected  int64
+}
+
+// counters counts the outcomes of the requests of a breaker and notifies its
+// listener of state changes.
+type counters struct {
+	successes atomic.Int64
+	failures  atomic.Int64
+	rejected  atomic.Int64
+	listener  func(from, to State)
+}
+
+func (c *counters) stats(s State) Stats {
+	return Stats{
+		State:     s,
+		Successes: c.successes.Load(),
+		Failures:  c.failures.Load(),
+		Rejected:  c.rejected.Load(),
+	}
+}
+
+func (c *counters) notify(from, to State) {
+	if from != to && c.listener != nil {
+		c.listener(from, to)
+	}
+}
+
+// observable is implemented by the breakers of this package, which report
+// their state changes to the listener set by Group.
+type observable interface {
+	setListener(fn func(from, to State))
+}
+
+func (c *counters) setListener(fn func(from, to State)) {
+	c.listener = fn
+}
+
+// State is the state of a breaker.
+type State int
+
+const (
+	// StateClosed lets requests through.
+	StateClosed State = iota
+	// StateOpen rejects requests.
+	StateOpen
+	// StateHalfOpen lets a limited number of probe requests through to test
+	// whether the dependency recovered.
+	StateHalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
@@ -0,0 +1,177 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSREThrottlesFailures(t *testing.T) {
+	b := NewSRE(WithMinRequests(10), WithWindow(time.Second, 10))
+	for range 20 {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("expected healthy requests to be allowed, got %v", err)
+		}
+		b.MarkSuccess()
+	}
+
+	for range 200 {
+		b.MarkFailed()
+	}
+	var rejected int
+	for range 100 {
+		if err := b.Allow(); errors.Is(err, ErrNotAllowed) {
+			rejected++
+		}
+	}
+	if rejected < 50 {
+		t.Fatalf("expected most requests to be rejected, got %d of 100", rejected)
+	}
+}
+
+func TestConsecutiveHalfOpenProbes(t *testing.T) {
+	b := NewConsecutive(WithFailureThreshold(3), WithOpenDuration(10*time.Millisecond), WithHalfOpenProbes(2))
+	for range 3 {
+		b.MarkFailed()
+	}
+	if err := b.Allow(); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("expected open breaker to reject, got %v", err)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if b.Allow() != nil || b.Allow() != nil {
+		t.Fatalf("expected 2 probes to be allowed")
+	}
+	if err := b.Allow(); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("expected a third probe to be rejected, got %v", err)
+	}
+	b.MarkSuccess()
+	b.MarkFailed()
+	if err := b.Allow(); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("expected a failed probe to reopen the breaker, got %v", err)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	b.Allow()
+	b.Allow()
+	b.MarkSuccess()
+	b.MarkSuccess()
+	for range 5 {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("expected closed breaker to allow, got %v", err)
+		}
+	}
+}
+
+func TestGroupIsolatesKeys(t *testing.T) {
+	g := NewGroup(func(string) Breaker { return NewConsecutive(WithFailureThreshold(1)) })
+	wantErr := errors.New("unavailable")
+
+	ctx := context.Background()
+	ok := func(context.Context) error { return nil }
+
+	if err := g.Do(ctx, "/users", func(context.Context) error { return wantErr }, nil); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	var fellBack error
+	err := g.Do(ctx, "/users", ok, func(_ context.Context, err error) error {
+		fellBack = err
+		return nil
+	})
+	if err != nil || !errors.Is(fellBack, ErrNotAllowed) {
+		t.Fatalf("expected fallback for %v, got %v (%v)", ErrNotAllowed, fellBack, err)
+	}
+	if err := g.Do(ctx, "/orders", ok, nil); err != nil {
+		t.Fatalf("expected other keys to be unaffected, got %v", err)
+	}
+}
+
+func TestGroupStateChangesAndStats(t *testing.T) {
+	type change struct {
+		key      string
+		from, to State
+	}
+	var changes []change
+	g := NewGroup(func(string) Breaker {
+		return NewConsecutive(WithFailureThreshold(2))
+	}, WithOnStateChange(func(key string, from, to State) {
+		changes = append(changes, change{key, from, to})
+	}))
+
+	ctx := context.Background()
+	fail := func(context.Context) error { return errors.New("unavailable") }
+	g.Do(ctx, "db", func(context.Context) error { return nil }, nil)
+	g.Do(ctx, "db", fail, nil)
+	g.Do(ctx, "db", fail, nil)
+	g.Do(ctx, "db", fail, nil)
+
+	if len(changes) != 1 || changes[0] != (change{"db", StateClosed, StateOpen}) {
+		t.Fatalf("expected db to change from closed to open, got %v", changes)
+	}
+	want := Stats{State: StateOpen, Successes: 1, Failures: 2, Rejected: 1}
+	if got := g.Stats()["db"]; got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestGroupIgnoredErrors(t *testing.T) {
+	errNotFound := errors.New("not found")
+	g := NewGroup(func(string) Breaker {
+		return NewConsecutive(WithFailureThreshold(1))
+	}, WithIgnoredErrors(func(err error) bool { return errors.Is(err, errNotFound) }))
+	ctx := context.Background()
+
+	fallback := func(context.Context, error) error { return errors.New("fallback") }
+	for range 3 {
+		err := g.Do(ctx, "users", func(context.Context) error { return errNotFound }, fallback)
+		if !errors.Is(err, errNotFound) {
+			t.Fatalf("expected %v without fallback, got %v", errNotFound, err)
+		}
+	}
+	if s := g.Stats()["users"]; s.State != StateClosed || s.Failures != 0 {
+		t.Fatalf("expected ignored errors not to count as failures, got %+v", s)
+	}
+}
+
+type chanWatcher chan Config
+
+func (w chanWatcher) Next() (Config, error) {
+	cfg, ok := <-w
+	if !ok {
+		return Config{}, errors.New("watcher stopped")
+	}
+	return cfg, nil
+}
+
+func (w chanWatcher) Stop() error {
+	return nil
+}
+
+func TestGroupWatchUpdatesConfig(t *testing.T) {
+	g := NewGroup(func(string) Breaker { return NewConsecutive(WithFailureThreshold(1)) })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := make(chanWatcher)
+	done := make(chan error)
+	go func() { done <- g.Watch(ctx, w) }()
+
+	g.Get("db")
+	w <- Config{FailureThreshold: 3}
+	close(w)
+	<-done
+
+	fail := func(context.Context) error { return errors.New("unavailable") }
+	for range 2 {
+		g.Do(ctx, "db", fail, nil)
+		g.Do(ctx, "cache", fail, nil)
+	}
+	stats := g.Stats()
+	if stats["db"].State != StateClosed || stats["cache"].State != StateClosed {
+		t.Fatalf("expected the updated threshold to keep breakers closed, got %+v", stats)
+	}
+	g.Do(ctx, "db", fail, nil)
+	if s := g.Get("db").Stats(); s.State != StateOpen {
+		t.Fatalf("expected breaker to open at the updated threshold, got %v", s.State)
+	}
+}
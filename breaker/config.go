@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"context"
+	"time"
+)
+
+// Config holds the tunable settings of breakers, e.g. when loaded from a
+// config center. Zero fields leave the current settings unchanged, and
+// settings not used by a breaker are ignored.
+type Config struct {
+	// K, MinRequests, Window and Buckets tune SRE breakers. Changing the
+	// window resets the statistics.
+	K           float64       `json:"k" yaml:"k"`
+	MinRequests int64         `json:"min_requests" yaml:"min_requests"`
+	Window      time.Duration `json:"window" yaml:"window"`
+	Buckets     int           `json:"buckets" yaml:"buckets"`
+	// FailureThreshold, OpenDuration and HalfOpenProbes tune consecutive
+	// failure breakers.
+	FailureThreshold int           `json:"failure_threshold" yaml:"failure_threshold"`
+	OpenDuration     time.Duration `json:"open_duration" yaml:"open_duration"`
+	HalfOpenProbes   int           `json:"half_open_probes" yaml:"half_open_probes"`
+}
+
+// Tunable is implemented by the breakers whose configuration can be updated
+// at runtime, such as the breakers of this package.
+type Tunable interface {
+	UpdateConfig(cfg Config)
+}
+
+// Watcher delivers configuration changes, e.g. from a config center.
+type Watcher interface {
+	// Next blocks until the configuration changes.
+	Next() (Config, error)
+	// Stop makes pending calls to Next return.
+	Stop() error
+}
+
+// UpdateConfig updates the breakers of the group, and those created later,
+// with cfg.
+func (g *Group) UpdateConfig(cfg Config) {
+	g.config.Store(&cfg)
+	g.breakers.Range(func(_ string, b Breaker) bool {
+		if t, ok := b.(Tunable); ok {
+			t.UpdateConfig(cfg)
+		}
+		return true
+	})
+}
+
+// Watch updates the configuration of the group with the changes delivered by
+// w until ctx is done or w fails, returning the error of w in the latter case.
+func (g *Group) Watch(ctx context.Context, w Watcher) error {
+	stop := context.AfterFunc(ctx, func() { w.Stop() })
+	defer stop()
+	for {
+		cfg, err := w.Next()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		g.UpdateConfig(cfg)
+	}
+}
+
+// UpdateConfig updates the settings of the breaker atomically.
+func (b *sre) UpdateConfig(cfg Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if cfg.K > 0 {
+		b.k = cfg.K
+	}
+	if cfg.MinRequests > 0 {
+		b.minRequests = cfg.MinRequests
+	}
+	resize := false
+	if cfg.Window > 0 && cfg.Window != b.size {
+		b.size, resize = cfg.Window, true
+	}
+	if cfg.Buckets > 0 && cfg.Buckets != b.buckets {
+		b.buckets, resize = cfg.Buckets, true
+	}
+	if resize {
+		b.counts = newWindow(b.size, b.buckets)
+	}
+}
+
+// UpdateConfig updates the settings of the breaker atomically.
+func (b *consecutive) UpdateConfig(cfg Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if cfg.FailureThreshold > 0 {
+		b.threshold = cfg.FailureThreshold
+	}
+	if cfg.OpenDuration > 0 {
+		b.openDuration = cfg.OpenDuration
+	}
+	if cfg.HalfOpenProbes > 0 {
+		b.probes = cfg.HalfOpenProbes
+	}
+}
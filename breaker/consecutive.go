@@ -0,0 +1,151 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// ConsecutiveOption is consecutive failure breaker option.
+type ConsecutiveOption func(*consecutive)
+
+// WithFailureThreshold overrides the number of consecutive failures opening
+// the breaker, 5 by default.
+func WithFailureThreshold(n int) ConsecutiveOption {
+	return func(b *consecutive) {
+		if n > 0 {
+			b.threshold = n
+		}
+	}
+}
+
+// WithOpenDuration overrides the time the breaker stays open before probing
+// the dependency, 10 seconds by default.
+func WithOpenDuration(d time.Duration) ConsecutiveOption {
+	return func(b *consecutive) {
+		if d > 0 {
+			b.openDuration = d
+		}
+	}
+}
+
+// WithHalfOpenProbes overrides the number of probe requests let through when
+// half-open, all of which must succeed to close the breaker, 1 by default.
+func WithHalfOpenProbes(n int) ConsecutiveOption {
+	return func(b *consecutive) {
+		if n > 0 {
+			b.probes = n
+		}
+	}
+}
+
+// NewConsecutive creates a classic three-state Breaker. It opens after
+// consecutive failures, rejects requests while open, and then lets a limited
+// number of probes through, closing again if they all succeed and reopening
+// on the first failure.
+func NewConsecutive(opts ...ConsecutiveOption) Breaker {
+	b := &consecutive{
+		threshold:    5,
+		openDuration: 10 * time.Second,
+		probes:       1,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+type consecutive struct {
+	counters
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	probes       int
+
+	state       State
+	consecutive int
+	openedAt    time.Time
+	probing     int
+	probed      int
+}
+
+// Allow rejects requests while open, and probes beyond the limit while half-open.
+func (b *consecutive) Allow() error {
+	b.mu.Lock()
+	from := b.state
+	err := b.allow()
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+	if err != nil {
+		b.rejected.Add(1)
+	}
+	return err
+}
+
+func (b *consecutive) allow() error {
+	if b.state == StateOpen {
+		if time.Since(b.openedAt) < b.openDuration {
+			return ErrNotAllowed
+		}
+		b.setState(StateHalfOpen)
+	}
+	if b.state == StateHalfOpen {
+		if b.probing >= b.probes {
+			return ErrNotAllowed
+		}
+		b.probing++
+	}
+	return nil
+}
+
+// MarkSuccess resets the failures, closing the breaker once every probe succeeded.
+func (b *consecutive) MarkSuccess() {
+	b.successes.Add(1)
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateClosed:
+		b.consecutive = 0
+	case StateHalfOpen:
+		if b.probed++; b.probed >= b.probes {
+			b.setState(StateClosed)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// MarkFailed counts a failure, opening the breaker at the threshold or when
+// a probe fails.
+func (b *consecutive) MarkFailed() {
+	b.failures.Add(1)
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateClosed:
+		if b.consecutive++; b.consecutive >= b.threshold {
+			b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		b.setState(StateOpen)
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// Stats returns a snapshot of the statistics of the breaker.
+func (b *consecutive) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.counters.stats(b.state)
+}
+
+func (b *consecutive) setState(s State) {
+	b.state = s
+	b.consecutive, b.probing, b.probed = 0, 0, 0
+	if s == StateOpen {
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,99 @@
+package breaker
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/go-kratos/kit/container/maps"
+)
+
+// GroupOption is group option.
+type GroupOption func(*Group)
+
+// WithOnStateChange sets a hook invoked when the breaker of a key changes its
+// state, e.g. to log or alert on tripped dependencies. Only the breakers of
+// this package report their state changes.
+func WithOnStateChange(fn func(key string, from, to State)) GroupOption {
+	return func(g *Group) {
+		g.onStateChange = fn
+	}
+}
+
+// WithIgnoredErrors excludes the errors for which ignored reports true, such as
+// business errors like NotFound, from the failure accounting of Do: they
+// count as successes and are returned without invoking the fallback.
+func WithIgnoredErrors(ignored func(err error) bool) GroupOption {
+	return func(g *Group) {
+		g.ignored = ignored
+	}
+}
+
+// Group holds a Breaker per key, such as a method name or target host, so that
+// each endpoint of a dependency is isolated from the failures of the others.
+type Group struct {
+	breakers      maps.Map[string, Breaker]
+	newBreaker    func(key string) Breaker
+	onStateChange func(key string, from, to State)
+	ignored       func(err error) bool
+	config        atomic.Pointer[Config]
+}
+
+// NewGroup creates a Group creating the breakers of keys with newBreaker on
+// first use, or SRE breakers with the default options if it is nil.
+func NewGroup(newBreaker func(key string) Breaker, opts ...GroupOption) *Group {
+	if newBreaker == nil {
+		newBreaker = func(string) Breaker { return NewSRE() }
+	}
+	g := &Group{newBreaker: newBreaker}
+	for _, o := range opts {
+		o(g)
+	}
+	return g
+}
+
+// Get returns the breaker of key, creating it if needed.
+func (g *Group) Get(key string) Breaker {
+	if b, ok := g.breakers.Load(key); ok {
+		return b
+	}
+	b := g.newBreaker(key)
+	if t, ok := b.(Tunable); ok && g.config.Load() != nil {
+		t.UpdateConfig(*g.config.Load())
+	}
+	if o, ok := b.(observable); ok && g.onStateChange != nil {
+		o.setListener(func(from, to State) { g.onStateChange(key, from, to) })
+	}
+	b, _ = g.breakers.LoadOrStore(key, b)
+	return b
+}
+
+// Stats returns a snapshot of the statistics of the breakers by key.
+func (g *Group) Stats() map[string]Stats {
+	stats := make(map[string]Stats)
+	g.breakers.Range(func(key string, b Breaker) bool {
+		stats[key] = b.Stats()
+		return true
+	})
+	return stats
+}
+
+// Do calls primary if the breaker of key allows it, recording its result.
+// When the breaker rejects the call with ErrNotAllowed or primary fails with
+// an error that is not ignored, the error is passed to fallback, if not nil,
+// whose result is returned instead.
+func (g *Group) Do(ctx context.Context, key string, primary func(context.Context) error, fallback func(context.Context, error) error) error {
+	b := g.Get(key)
+	err := b.Allow()
+	if err == nil {
+		err = primary(ctx)
+		if err == nil || g.ignored != nil && g.ignored(err) {
+			b.MarkSuccess()
+			return err
+		}
+		b.MarkFailed()
+	}
+	if fallback != nil {
+		return fallback(ctx, err)
+	}
+	return err
+}
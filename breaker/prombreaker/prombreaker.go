@@ -0,0 +1,52 @@
+// Package prombreaker exports circuit breaker statistics to Prometheus.
+package prombreaker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/go-kratos/kit/breaker"
+)
+
+// Collector is a prometheus.Collector exporting the state and request counts
+// of the breakers of a group, labeled with their keys.
+type Collector struct {
+	group    *breaker.Group
+	state    *prometheus.Desc
+	requests *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector for the breakers of g with metrics in the
+// given namespace.
+func NewCollector(g *breaker.Group, namespace string) *Collector {
+	return &Collector{
+		group: g,
+		state: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "breaker", "state"),
+			"State of the breaker: 0 closed, 1 open, 2 half-open.",
+			[]string{"key"}, nil,
+		),
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "breaker", "requests_total"),
+			"Number of requests by result: success, failure or rejected.",
+			[]string{"key", "result"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.state
+	ch <- c.requests
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for key, s := range c.group.Stats() {
+		ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, float64(s.State), key)
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Successes), key, "success")
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Failures), key, "failure")
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Rejected), key, "rejected")
+	}
+}
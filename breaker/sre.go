@@ -0,0 +1,119 @@
+package breaker
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Option is SRE breaker option.
+type Option func(*sre)
+
+// WithK overrides the multiplier of the accepted requests, 1.5 by default.
+// Lower values throttle more aggressively.
+func WithK(k float64) Option {
+	return func(b *sre) {
+		if k > 0 {
+			b.k = k
+		}
+	}
+}
+
+// WithMinRequests overrides the number of requests within the window below
+// which no request is rejected, 100 by default.
+func WithMinRequests(n int64) Option {
+	return func(b *sre) {
+		if n > 0 {
+			b.minRequests = n
+		}
+	}
+}
+
+// WithWindow overrides the rolling window of the statistics, 3 seconds in
+// 10 buckets by default.
+func WithWindow(size time.Duration, buckets int) Option {
+	return func(b *sre) {
+		if size > 0 && buckets > 0 {
+			b.size, b.buckets = size, buckets
+		}
+	}
+}
+
+// NewSRE creates a Breaker implementing the adaptive client-side throttling
+// of the Google SRE book. It rejects requests with probability
+// max(0, (requests - K*accepts) / (requests + 1)), counted over the window.
+func NewSRE(opts ...Option) Breaker {
+	b := &sre{
+		k:           1.5,
+		minRequests: 100,
+		size:        3 * time.Second,
+		buckets:     10,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	b.counts = newWindow(b.size, b.buckets)
+	return b
+}
+
+type sre struct {
+	counters
+	state       atomic.Int32
+	mu          sync.RWMutex
+	k           float64
+	minRequests int64
+	size        time.Duration
+	buckets     int
+	counts      *window
+}
+
+// Allow rejects the request with the probability of the throttling formula.
+// Rejected requests count as requests not accepted by the dependency. The
+// breaker is reported open while the probability is positive.
+func (b *sre) Allow() error {
+	now := time.Now()
+	b.mu.RLock()
+	k, minRequests, counts := b.k, b.minRequests, b.counts
+	b.mu.RUnlock()
+	requests, accepts := counts.sum(now)
+	p := 0.0
+	if requests >= minRequests {
+		p = math.Max(0, (float64(requests)-k*float64(accepts))/float64(requests+1))
+	}
+	to := StateClosed
+	if p > 0 {
+		to = StateOpen
+	}
+	b.notify(State(b.state.Swap(int32(to))), to)
+	if p > 0 && rand.Float64() < p {
+		counts.add(now, false)
+		b.rejected.Add(1)
+		return ErrNotAllowed
+	}
+	return nil
+}
+
+// MarkSuccess records a request accepted by the dependency.
+func (b *sre) MarkSuccess() {
+	b.window().add(time.Now(), true)
+	b.successes.Add(1)
+}
+
+// MarkFailed records a request rejected by the dependency.
+func (b *sre) MarkFailed() {
+	b.window().add(time.Now(), false)
+	b.failures.Add(1)
+}
+
+// Stats returns a snapshot of the statistics of the breaker.
+func (b *sre) Stats() Stats {
+	return b.counters.stats(State(b.state.Load()))
+}
+
+func (b *sre) window() *window {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.counts
+}
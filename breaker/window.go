@@ -0,0 +1,53 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// window counts the requests and accepted requests within a rolling window
+// of buckets.
+type window struct {
+	mu      sync.Mutex
+	bucket  time.Duration
+	buckets []windowBucket
+}
+
+type windowBucket struct {
+	start    time.Time
+	requests int64
+	accepts  int64
+}
+
+func newWindow(size time.Duration, buckets int) *window {
+	return &window{bucket: size / time.Duration(buckets), buckets: make([]windowBucket, buckets)}
+}
+
+// add records a request, accepted by the dependency or not.
+func (w *window) add(now time.Time, accepted bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	i := int(now.UnixNano()/int64(w.bucket)) % len(w.buckets)
+	b := &w.buckets[i]
+	if start := now.Truncate(w.bucket); !b.start.Equal(start) {
+		*b = windowBucket{start: start}
+	}
+	b.requests++
+	if accepted {
+		b.accepts++
+	}
+}
+
+// sum returns the number of requests and accepted requests within the window.
+func (w *window) sum(now time.Time) (requests, accepts int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	size := w.bucket * time.Duration(len(w.buckets))
+	for _, b := range w.buckets {
+		if now.Sub(b.start) < size {
+			requests += b.requests
+			accepts += b.accepts
+		}
+	}
+	return requests, accepts
+}
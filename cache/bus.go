@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// Transport broadcasts invalidation messages between the replicas of a
+// service, such as Redis pub/sub.
+type Transport interface {
+	// Publish sends payload to all subscribers, including the publisher.
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe returns the payloads published from now on, until ctx is
+	// done, when the channel is closed.
+	Subscribe(ctx context.Context) (<-chan []byte, error)
+}
+
+// Deleter is a cache keyed by string, such as a Cache[string, V], a
+// Loading[string, V] or the Local level of a Tiered cache.
+type Deleter interface {
+	Delete(key string)
+}
+
+// Bus propagates the invalidation of keys to the caches of other replicas,
+// so that they do not serve stale values after a replica writes.
+type Bus struct {
+	transport Transport
+	id        string
+
+	mu     sync.RWMutex
+	caches []Deleter
+}
+
+type invalidation struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys"`
+}
+
+// NewBus creates a Bus broadcasting invalidations over t. Run must be called
+// to apply the invalidations of other replicas.
+func NewBus(t Transport) *Bus {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &Bus{transport: t, id: hex.EncodeToString(id)}
+}
+
+// Register adds caches whose keys are invalidated by the bus.
+func (b *Bus) Register(caches ...Deleter) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.caches = append(b.caches, caches...)
+}
+
+// Invalidate removes keys from the registered caches and publishes them to
+// the other replicas, typically after updating or deleting their values.
+func (b *Bus) Invalidate(ctx context.Context, keys ...string) error {
+	b.delete(keys)
+	payload, err := json.Marshal(invalidation{Source: b.id, Keys: keys})
+	if err != nil {
+		return err
+	}
+	return b.transport.Publish(ctx, payload)
+}
+
+// Run removes the keys invalidated by other replicas from the registered
+// caches until ctx is done. Malformed messages are ignored.
+func (b *Bus) Run(ctx context.Context) error {
+	ch, err := b.transport.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	for payload := range ch {
+		var msg invalidation
+		if json.Unmarshal(payload, &msg) != nil || msg.Source == b.id {
+			continue
+		}
+		b.delete(msg.Keys)
+	}
+	return ctx.Err()
+}
+
+func (b *Bus) delete(keys []string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, c := range b.caches {
+		for _, key := range keys {
+			c.Delete(key)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package cache
+
+// Cache is a bounded in-process cache.
+type Cache[K comparable, V any] interface {
+	// Get returns the value of key, if cached.
+	Get(key K) (V, bool)
+	// Set caches the value of key, possibly evicting other entries.
+	Set(key K, value V)
+	// Delete removes key from the cache.
+	Delete(key K)
+	// Len returns the number of cached entries.
+	Len() int
+	// Stats returns a snapshot of the statistics of the cache.
+	Stats() Stats
+}
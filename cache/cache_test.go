@@ -0,0 +1,732 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kit/retry"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+	c.Set("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected %v, got %v", 1, v)
+	}
+	c.Delete("a")
+	if c.Len() != 1 {
+		t.Fatalf("expected %v, got %v", 1, c.Len())
+	}
+}
+
+func TestTinyLFU(t *testing.T) {
+	c := NewTinyLFU[string, int](10)
+	c.Set("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected %v, got %v", 1, v)
+	}
+	c.Set("a", 2)
+	if v, _ := c.Get("a"); v != 2 {
+		t.Fatalf("expected %v, got %v", 2, v)
+	}
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a to be deleted")
+	}
+	for i := range 100 {
+		c.Set(strconv.Itoa(i), i)
+	}
+	if c.Len() > 10 {
+		t.Fatalf("expected at most %v entries, got %v", 10, c.Len())
+	}
+}
+
+func TestTinyLFUScanResistance(t *testing.T) {
+	// Hot keys are accessed between the keys of a scan, which flushes an LRU
+	// as more distinct keys than its capacity are accessed in between.
+	hitRatio := func(c Cache[int, int]) float64 {
+		hits, gets := 0, 0
+		for i := range 20000 {
+			hot := i % 80
+			if _, ok := c.Get(hot); ok {
+				hits++
+			} else {
+				c.Set(hot, hot)
+			}
+			c.Get(1000 + i)
+			c.Set(1000+i, i)
+			if i >= 10000 {
+				gets++
+			}
+			if i == 9999 {
+				hits = 0
+			}
+		}
+		return float64(hits) / float64(gets)
+	}
+	if r := hitRatio(NewLRU[int, int](100)); r > 0.1 {
+		t.Fatalf("expected LRU to thrash, got hit ratio %v", r)
+	}
+	if r := hitRatio(NewTinyLFU[int, int](100)); r < 0.8 {
+		t.Fatalf("expected hot keys to stay cached, got hit ratio %v", r)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	c := NewTTL[string, int](20*time.Millisecond, WithSweepInterval(5*time.Millisecond))
+	defer c.Close()
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected %v, got %v", 1, v)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a to be expired")
+	}
+	if c.Len() != 1 {
+		t.Fatalf("expected expired entries to be swept, got %v entries", c.Len())
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("expected %v, got %v", 2, v)
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	c := NewTTL[int, int](time.Hour, WithJitter(0.5), WithSweepInterval(0))
+	defer c.Close()
+	expiries := make(map[time.Time]bool)
+	for i := range 10 {
+		c.Set(i, i)
+		e := c.items[i].expiry
+		if d := time.Until(e); d > time.Hour || d < 30*time.Minute-time.Second {
+			t.Fatalf("expected expiry within jitter, got %v", d)
+		}
+		expiries[e] = true
+	}
+	if len(expiries) < 2 {
+		t.Fatalf("expected jittered expiries, got %v", expiries)
+	}
+}
+
+func TestLoadingSingleflight(t *testing.T) {
+	var loads atomic.Int32
+	release := make(chan struct{})
+	c := NewLoading(func(ctx context.Context, key string) (int, error) {
+		loads.Add(1)
+		<-release
+		return len(key), nil
+	})
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.Get(context.Background(), "abc"); err != nil || v != 3 {
+				t.Errorf("expected %v, got %v, %v", 3, v, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("expected %v load, got %v", 1, n)
+	}
+	if _, err := c.Get(context.Background(), "abc"); err != nil || loads.Load() != 1 {
+		t.Fatalf("expected cached value, got %v loads", loads.Load())
+	}
+}
+
+func TestLoadingErrorTTL(t *testing.T) {
+	errLoad := errors.New("load failed")
+	var loads atomic.Int32
+	c := NewLoading(func(ctx context.Context, key string) (int, error) {
+		loads.Add(1)
+		return 0, errLoad
+	}, WithErrorTTL(20*time.Millisecond))
+	for range 3 {
+		if _, err := c.Get(context.Background(), "a"); !errors.Is(err, errLoad) {
+			t.Fatalf("expected %v, got %v", errLoad, err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("expected %v load, got %v", 1, n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	c.Get(context.Background(), "a")
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("expected %v loads, got %v", 2, n)
+	}
+}
+
+func TestLoadingCanceledLeader(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	c := NewLoading(func(ctx context.Context, key string) (int, error) {
+		close(started)
+		<-release
+		return len(key), ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := c.Get(ctx, "abc")
+		leader <- err
+	}()
+	<-started
+	follower := make(chan error, 1)
+	go func() {
+		v, err := c.Get(context.Background(), "abc")
+		if err == nil && v != 3 {
+			err = fmt.Errorf("expected %v, got %v", 3, v)
+		}
+		follower <- err
+	}()
+	cancel()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	close(release)
+	if err := <-follower; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestLoadingSetDuringLoad(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var loads atomic.Int32
+	c := NewLoading(func(ctx context.Context, key string) (int, error) {
+		if loads.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return 1, nil
+	})
+	done := make(chan struct{})
+	go func() {
+		c.Get(context.Background(), "a")
+		close(done)
+	}()
+	<-started
+	c.Set("a", 2)
+	close(release)
+	<-done
+	if v, err := c.Get(context.Background(), "a"); err != nil || v != 2 {
+		t.Fatalf("expected %v, got %v, %v", 2, v, err)
+	}
+
+	started, release, done = make(chan struct{}), make(chan struct{}), make(chan struct{})
+	loads.Store(0)
+	c.Delete("a")
+	go func() {
+		c.Get(context.Background(), "a")
+		close(done)
+	}()
+	<-started
+	c.Delete("a")
+	close(release)
+	<-done
+	if c.Len() != 0 {
+		t.Fatalf("expected the superseded load not to be cached, got %v entries", c.Len())
+	}
+}
+
+func TestLoadingPanic(t *testing.T) {
+	release := make(chan struct{})
+	c := NewLoading(func(ctx context.Context, key string) (int, error) {
+		<-release
+		panic("boom")
+	})
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := c.Get(context.Background(), "a")
+			errs <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for range 2 {
+		if err := <-errs; err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("expected panic error, got %v", err)
+		}
+	}
+}
+
+func TestLoadingRefreshAfterWrite(t *testing.T) {
+	var loads atomic.Int32
+	c := NewLoading(func(ctx context.Context, key string) (int32, error) {
+		return loads.Add(1), nil
+	}, WithRefreshAfterWrite(10*time.Millisecond))
+	if v, _ := c.Get(context.Background(), "a"); v != 1 {
+		t.Fatalf("expected %v, got %v", 1, v)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if v, _ := c.Get(context.Background(), "a"); v != 1 {
+		t.Fatalf("expected stale value %v, got %v", 1, v)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if v, _ := c.Get(context.Background(), "a"); v != 2 {
+		t.Fatalf("expected refreshed value %v, got %v", 2, v)
+	}
+}
+
+type memRemote struct {
+	mu    sync.Mutex
+	items map[string][]byte
+	err   error
+}
+
+func (r *memRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	v, ok := r.items[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return v, nil
+}
+
+func (r *memRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.items[key] = value
+	return nil
+}
+
+func (r *memRemote) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, key)
+	return r.err
+}
+
+func TestTiered(t *testing.T) {
+	type user struct{ Name string }
+	remote := &memRemote{items: make(map[string][]byte)}
+	load := func(ctx context.Context, key string) (user, error) {
+		return user{Name: key}, nil
+	}
+	ctx := context.Background()
+	a := NewTiered(remote, load, WithLocalTTL(time.Hour), WithRemoteTTL(time.Hour))
+	defer a.Close()
+	if u, err := a.Get(ctx, "alice"); err != nil || u.Name != "alice" {
+		t.Fatalf("expected %v, got %v, %v", "alice", u, err)
+	}
+	a.Get(ctx, "alice")
+	if s := a.LevelStats(); s.Local.Hits != 1 || s.Remote.Misses != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if s := a.Stats(); s.Hits != 1 || s.Misses != 1 || s.Loads != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if string(remote.items["alice"]) != `{"Name":"alice"}` {
+		t.Fatalf("expected the remote level to be filled, got %s", remote.items["alice"])
+	}
+
+	// Another replica reads through the remote level.
+	b := NewTiered(remote, func(ctx context.Context, key string) (user, error) {
+		return user{}, errors.New("unexpected load")
+	})
+	defer b.Close()
+	if u, err := b.Get(ctx, "alice"); err != nil || u.Name != "alice" {
+		t.Fatalf("expected %v, got %v, %v", "alice", u, err)
+	}
+	if s := b.Stats(); s.Hits != 1 || s.Loads != 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	remote.err = errors.New("unreachable")
+	if u, err := a.Get(ctx, "bob"); err != nil || u.Name != "bob" {
+		t.Fatalf("expected %v, got %v, %v", "bob", u, err)
+	}
+	if s := a.LevelStats(); s.RemoteErrors != 2 {
+		t.Fatalf("expected %v remote errors, got %v", 2, s.RemoteErrors)
+	}
+}
+
+func TestTieredLocalLevel(t *testing.T) {
+	remote := &memRemote{items: make(map[string][]byte)}
+	var loads atomic.Int32
+	load := func(ctx context.Context, key string) (string, error) {
+		loads.Add(1)
+		return key, nil
+	}
+	ctx := context.Background()
+	c := NewTiered(remote, load, WithLocalCapacity(1), WithRemoteTTL(0))
+	defer c.Close()
+	c.Get(ctx, "a")
+	c.Get(ctx, "a")
+	if s := c.LevelStats(); s.Local.Hits != 1 {
+		t.Fatalf("expected the local level to serve without a remote TTL, got %+v", s.Local)
+	}
+	c.Get(ctx, "b")
+	if s := c.LevelStats(); s.Local.Evictions != 1 {
+		t.Fatalf("expected %v local eviction, got %+v", 1, s.Local)
+	}
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("expected %v loads, got %v", 2, n)
+	}
+}
+
+func TestLoadingExpireAfterWrite(t *testing.T) {
+	var loads atomic.Int32
+	c := NewLoading(func(ctx context.Context, key string) (int32, error) {
+		return loads.Add(1), nil
+	}, WithExpireAfterWrite(10*time.Millisecond))
+	c.Get(context.Background(), "a")
+	time.Sleep(20 * time.Millisecond)
+	if v, _ := c.Get(context.Background(), "a"); v != 2 {
+		t.Fatalf("expected reloaded value %v, got %v", 2, v)
+	}
+}
+
+func TestLoadingEarlyRefresh(t *testing.T) {
+	var loads atomic.Int32
+	c := NewLoading(func(ctx context.Context, key string) (int32, error) {
+		time.Sleep(50 * time.Millisecond)
+		return loads.Add(1), nil
+	}, WithExpireAfterWrite(time.Hour), WithEarlyRefresh(1e9))
+	c.Get(context.Background(), "a")
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.Get(context.Background(), "a"); err != nil || v != 1 {
+				t.Errorf("expected current value %v, got %v, %v", 1, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	time.Sleep(80 * time.Millisecond)
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("expected a single early refresh, got %v loads", n)
+	}
+	if v, _ := c.Get(context.Background(), "a"); v != 2 {
+		t.Fatalf("expected refreshed value %v, got %v", 2, v)
+	}
+}
+
+func TestLoadingNotFoundTTL(t *testing.T) {
+	var loads atomic.Int32
+	c := NewLoading(func(ctx context.Context, key string) (int, error) {
+		loads.Add(1)
+		return 0, fmt.Errorf("user %s: %w", key, ErrNotFound)
+	}, WithNotFoundTTL(20*time.Millisecond), WithErrorTTL(time.Hour))
+	for range 3 {
+		if _, err := c.Get(context.Background(), "a"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected %v, got %v", ErrNotFound, err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("expected %v load, got %v", 1, n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	c.Get(context.Background(), "a")
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("expected not found results to expire first, got %v loads", n)
+	}
+}
+
+func TestStats(t *testing.T) {
+	caches := map[string]Cache[int, int]{
+		"lru":     NewLRU[int, int](1),
+		"tinylfu": NewTinyLFU[int, int](2),
+	}
+	for name, c := range caches {
+		c.Set(1, 1)
+		c.Get(1)
+		c.Get(2)
+		for i := range 10 {
+			c.Set(10+i, i)
+		}
+		s := c.Stats()
+		if s.Hits != 1 || s.Misses != 1 || s.Evictions == 0 {
+			t.Fatalf("%s: unexpected stats %+v", name, s)
+		}
+		if r := s.HitRatio(); r != 0.5 {
+			t.Fatalf("%s: expected %v, got %v", name, 0.5, r)
+		}
+	}
+
+	c := NewLoading(func(ctx context.Context, key int) (int, error) {
+		time.Sleep(time.Millisecond)
+		if key < 0 {
+			return 0, errors.New("negative")
+		}
+		return key, nil
+	}, WithCapacity(1))
+	c.Get(context.Background(), 1)
+	c.Get(context.Background(), 1)
+	c.Get(context.Background(), 2)
+	c.Get(context.Background(), -1)
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 3 || s.Loads != 3 || s.LoadFailures != 1 || s.Evictions != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if s.MeanLoadTime() < time.Millisecond {
+		t.Fatalf("expected mean load time of at least %v, got %v", time.Millisecond, s.MeanLoadTime())
+	}
+}
+
+func TestSharded(t *testing.T) {
+	c := NewSharded(5, func() Cache[int, int] { return NewLRU[int, int](10) })
+	if n := len(c.(*sharded[int, int]).shards); n != 8 {
+		t.Fatalf("expected %v shards, got %v", 8, n)
+	}
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				c.Set(g*100+i, i)
+				c.Get(g*100 + i)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := c.Len(); n > 80 {
+		t.Fatalf("expected at most %v entries, got %v", 80, n)
+	}
+	c.Set(1, 1)
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("expected %v, got %v", 1, v)
+	}
+	c.Delete(1)
+	if _, ok := c.Get(1); ok {
+		t.Fatalf("expected 1 to be deleted")
+	}
+	if s := c.Stats(); s.Hits+s.Misses != 802 || s.Evictions == 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+}
+
+func TestWriteThrough(t *testing.T) {
+	store := make(map[string]int)
+	errWrite := errors.New("write failed")
+	c := NewWriteThrough(NewLRU[string, int](10), func(ctx context.Context, key string, value int) error {
+		if value < 0 {
+			return errWrite
+		}
+		store[key] = value
+		return nil
+	})
+	if err := c.Set(context.Background(), "a", 1); err != nil || store["a"] != 1 {
+		t.Fatalf("expected the value to be written, got %v, %v", store, err)
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected %v, got %v", 1, v)
+	}
+	if err := c.Set(context.Background(), "a", -1); !errors.Is(err, errWrite) {
+		t.Fatalf("expected %v, got %v", errWrite, err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a failed write to invalidate the cached value")
+	}
+}
+
+func TestWriteBehind(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []map[string]int
+		fails   = 1
+	)
+	c := NewWriteBehind(NewLRU[string, int](10), func(ctx context.Context, batch map[string]int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fails > 0 {
+			fails--
+			return errors.New("store unavailable")
+		}
+		batches = append(batches, batch)
+		return nil
+	}, WithQueueSize(2), WithBatchSize(10), WithFlushInterval(time.Hour),
+		WithFlushRetry(retry.New(2, retry.WithBaseDelay(time.Millisecond))))
+	c.Set("a", 1)
+	c.Set("a", 2)
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Fatalf("expected %v, got %v", 2, v)
+	}
+	c.Set("b", 1)
+	if err := c.Set("c", 1); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected %v, got %v", ErrQueueFull, err)
+	}
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := c.Set("d", 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected %v, got %v", ErrClosed, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	written := make(map[string]int)
+	for _, b := range batches {
+		maps.Copy(written, b)
+	}
+	if written["a"] != 2 || written["b"] != 1 {
+		t.Fatalf("expected coalesced writes to be flushed, got %v", batches)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	weigh := func(key string, value []byte) int64 { return int64(len(value)) }
+	caches := map[string]Cache[string, []byte]{
+		"lru":     NewWeightedLRU(100, weigh),
+		"tinylfu": NewWeightedTinyLFU(100, weigh),
+	}
+	for name, c := range caches {
+		for i := range 50 {
+			c.Set(strconv.Itoa(i), make([]byte, 1+i%30))
+		}
+		var cost int64
+		for i := range 50 {
+			if v, ok := c.Get(strconv.Itoa(i)); ok {
+				cost += int64(len(v))
+			}
+		}
+		if cost > 100 || cost == 0 {
+			t.Fatalf("%s: expected a total cost within %v, got %v", name, 100, cost)
+		}
+		c.Set("huge", make([]byte, 101))
+		if _, ok := c.Get("huge"); ok {
+			t.Fatalf("%s: expected an entry costing more than the capacity not to be cached", name)
+		}
+	}
+}
+
+func TestBusTiered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	remote := &memRemote{items: make(map[string][]byte)}
+	var version atomic.Int32
+	load := func(ctx context.Context, key string) (int32, error) {
+		return version.Add(1), nil
+	}
+	a := NewTiered(remote, load, WithLocalTTL(time.Hour))
+	defer a.Close()
+	b := NewTiered(remote, load, WithLocalTTL(time.Hour))
+	defer b.Close()
+	transport := &memTransport{}
+	busA, busB := NewBus(transport), NewBus(transport)
+	busA.Register(a.Local())
+	busB.Register(b.Local())
+	go busA.Run(ctx)
+	go busB.Run(ctx)
+	time.Sleep(10 * time.Millisecond)
+
+	if v, err := b.Get(ctx, "k"); err != nil || v != 1 {
+		t.Fatalf("expected %v, got %v, %v", 1, v, err)
+	}
+	if err := a.Set(ctx, "k", 2); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := busA.Invalidate(ctx, "k"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		v, err := b.Get(ctx, "k")
+		if err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if v == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the local value of b to be invalidated, got %v", v)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type memTransport struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+func (t *memTransport) Publish(ctx context.Context, payload []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, ch := range t.subs {
+		ch <- payload
+	}
+	return nil
+}
+
+func (t *memTransport) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ch := make(chan []byte, 10)
+	t.subs = append(t.subs, ch)
+	context.AfterFunc(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.subs = slices.DeleteFunc(t.subs, func(c chan []byte) bool { return c == ch })
+		close(ch)
+	})
+	return ch, nil
+}
+
+func TestBus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport := &memTransport{}
+	a, b := NewLRU[string, int](10), NewLRU[string, int](10)
+	busA, busB := NewBus(transport), NewBus(transport)
+	busA.Register(a)
+	busB.Register(b)
+	done := make(chan error, 2)
+	for _, bus := range []*Bus{busA, busB} {
+		go func() { done <- bus.Run(ctx) }()
+	}
+	time.Sleep(10 * time.Millisecond)
+	a.Set("k", 1)
+	b.Set("k", 1)
+	if err := busA.Invalidate(ctx, "k"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if _, ok := a.Get("k"); ok {
+		t.Fatalf("expected the local entry to be invalidated")
+	}
+	deadline := time.Now().Add(time.Second)
+	for b.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if _, ok := b.Get("k"); ok {
+		t.Fatalf("expected the remote entry to be invalidated")
+	}
+	cancel()
+	for range 2 {
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"encoding/json"
+
+	"github.com/go-kratos/kit/container/maps"
+)
+
+// Codec serializes the values of caches stored out of process.
+type Codec interface {
+	// Marshal returns the encoding of v.
+	Marshal(v any) ([]byte, error)
+	// Unmarshal parses the encoded data into v.
+	Unmarshal(data []byte, v any) error
+	// Name returns the name the codec is registered by.
+	Name() string
+}
+
+var codecs = maps.New[string, Codec]()
+
+func init() {
+	RegisterCodec(jsonCodec{})
+}
+
+// RegisterCodec registers c by its name, replacing any codec of that name.
+// The "json" codec is registered by default.
+func RegisterCodec(c Codec) {
+	codecs.Store(c.Name(), c)
+}
+
+// GetCodec returns the codec registered by name, or nil.
+func GetCodec(name string) Codec {
+	c, _ := codecs.Load(name)
+	return c
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
@@ -0,0 +1,274 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// LoadingOption is loading cache option.
+type LoadingOption func(*loadingOptions)
+
+type loadingOptions struct {
+	capacity     int
+	refreshAfter time.Duration
+	expireAfter  time.Duration
+	beta         float64
+	errorTTL     time.Duration
+	notFoundTTL  time.Duration
+}
+
+// WithCapacity sets the maximum number of entries of the loading cache,
+// evicting the least recently used ones. The default is 1024.
+func WithCapacity(n int) LoadingOption {
+	return func(o *loadingOptions) {
+		o.capacity = n
+	}
+}
+
+// WithRefreshAfterWrite reloads entries older than d in the background on
+// access, while the stale value keeps being returned. A failed refresh keeps
+// the stale value. Refreshing is disabled by default.
+func WithRefreshAfterWrite(d time.Duration) LoadingOption {
+	return func(o *loadingOptions) {
+		o.refreshAfter = d
+	}
+}
+
+// WithExpireAfterWrite expires entries d after they are loaded, so that Get
+// loads them again. Entries do not expire by default.
+func WithExpireAfterWrite(d time.Duration) LoadingOption {
+	return func(o *loadingOptions) {
+		o.expireAfter = d
+	}
+}
+
+// WithEarlyRefresh refreshes entries in the background before they expire,
+// with a probability growing as the expiry approaches and with the time the
+// entry took to load (XFetch). A hot key is thus refreshed by a single caller
+// before its expiry rather than reloaded by all of them at once. beta scales
+// how early refreshes happen, 1 being the usual choice. It requires
+// WithExpireAfterWrite.
+func WithEarlyRefresh(beta float64) LoadingOption {
+	return func(o *loadingOptions) {
+		o.beta = beta
+	}
+}
+
+// WithErrorTTL caches load errors for d, so that a failing backend is not
+// called again by every Get. Errors are not cached by default.
+func WithErrorTTL(d time.Duration) LoadingOption {
+	return func(o *loadingOptions) {
+		o.errorTTL = d
+	}
+}
+
+// WithNotFoundTTL caches for d the results of loads failing with an error
+// matching ErrNotFound, which the loader returns for nonexistent keys, so that
+// repeated lookups of such keys do not reach the backend. It takes precedence
+// over WithErrorTTL, allowing not found results to be cached for a shorter
+// time than other values. A refresh finding a key removed replaces its value.
+// Not found results are not cached by default.
+func WithNotFoundTTL(d time.Duration) LoadingOption {
+	return func(o *loadingOptions) {
+		o.notFoundTTL = d
+	}
+}
+
+// Loading is a cache loading missing values with a loader. Concurrent Get
+// calls for the same missing key share a single load.
+type Loading[K comparable, V any] struct {
+	load         func(ctx context.Context, key K) (V, error)
+	entries      Cache[K, *loaded[V]]
+	refreshAfter time.Duration
+	expireAfter  time.Duration
+	beta         float64
+	errorTTL     time.Duration
+	notFoundTTL  time.Duration
+	counters
+
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+type loaded[V any] struct {
+	value    V
+	err      error
+	loadedAt time.Time
+	// ttl is how long err is cached.
+	ttl time.Duration
+	// delta is how long the value took to load.
+	delta time.Duration
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// stale is set when Set or Delete supersede the load, whose result is
+	// then returned to its callers but not cached.
+	stale bool
+}
+
+// NewLoading creates a Loading cache loading the values of missing keys with load.
+func NewLoading[K comparable, V any](load func(ctx context.Context, key K) (V, error), opts ...LoadingOption) *Loading[K, V] {
+	o := loadingOptions{capacity: 1024}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Loading[K, V]{
+		load:         load,
+		entries:      NewLRU[K, *loaded[V]](o.capacity),
+		refreshAfter: o.refreshAfter,
+		expireAfter:  o.expireAfter,
+		beta:         o.beta,
+		errorTTL:     o.errorTTL,
+		notFoundTTL:  o.notFoundTTL,
+		calls:        make(map[K]*call[V]),
+	}
+}
+
+// Get returns the value of key, loading it if it is missing or expired. It
+// returns the cached error of a recently failed load, such as ErrNotFound, if
+// errors are cached.
+func (c *Loading[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if e, ok := c.entries.Get(key); ok {
+		age := time.Since(e.loadedAt)
+		if e.err == nil && (c.expireAfter <= 0 || age < c.expireAfter) {
+			c.recordLookup(true)
+			if c.refreshAfter > 0 && age >= c.refreshAfter || c.refreshEarly(e, age) {
+				c.refresh(ctx, key)
+			}
+			return e.value, nil
+		}
+		if e.err != nil && age < e.ttl {
+			c.recordLookup(true)
+			return e.value, e.err
+		}
+	}
+	c.recordLookup(false)
+	return c.do(ctx, key)
+}
+
+// refreshEarly reports whether an entry of the given age should be refreshed
+// before it expires, if its remaining time to live is shorter than its load
+// time scaled by beta and an exponentially distributed random factor.
+func (c *Loading[K, V]) refreshEarly(e *loaded[V], age time.Duration) bool {
+	if c.beta <= 0 || c.expireAfter <= 0 {
+		return false
+	}
+	gap := float64(e.delta) * c.beta * -math.Log(rand.Float64())
+	return gap >= float64(c.expireAfter-age)
+}
+
+// Set caches the value of key, replacing any loaded value. A load of key in
+// flight is not cached when it completes.
+func (c *Loading[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(key)
+	c.entries.Set(key, &loaded[V]{value: value, loadedAt: time.Now()})
+}
+
+// Delete removes key from the cache, so that it is loaded again on next Get.
+// A load of key in flight is not cached when it completes.
+func (c *Loading[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(key)
+	c.entries.Delete(key)
+}
+
+// invalidate marks the load of key in flight as stale and detaches it, so
+// that the next Get starts a new load. c.mu must be held.
+func (c *Loading[K, V]) invalidate(key K) {
+	if cl, ok := c.calls[key]; ok {
+		cl.stale = true
+		delete(c.calls, key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *Loading[K, V]) Len() int {
+	return c.entries.Len()
+}
+
+// Stats returns a snapshot of the statistics of the cache. Cached errors
+// count as hits, and refreshes as loads.
+func (c *Loading[K, V]) Stats() Stats {
+	s := c.snapshot()
+	s.Evictions = c.entries.Stats().Evictions
+	return s
+}
+
+// refresh reloads key in the background unless it is already being loaded.
+func (c *Loading[K, V]) refresh(ctx context.Context, key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.calls[key]; ok {
+		return
+	}
+	cl := &call[V]{done: make(chan struct{})}
+	c.calls[key] = cl
+	go c.run(context.WithoutCancel(ctx), key, cl, true)
+}
+
+// do loads key, or waits for the load in flight, until ctx is done. The
+// shared load is not canceled with ctx, so a canceled caller does not fail
+// the others waiting for the key.
+func (c *Loading[K, V]) do(ctx context.Context, key K) (V, error) {
+	c.mu.Lock()
+	cl, ok := c.calls[key]
+	if !ok {
+		cl = &call[V]{done: make(chan struct{})}
+		c.calls[key] = cl
+		go c.run(context.WithoutCancel(ctx), key, cl, false)
+	}
+	c.mu.Unlock()
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// run loads key into cl and caches the result. A failed refresh does not
+// replace the stale value unless the key is not found. A panicking load is
+// reported as an error and not cached, nor is a load superseded by Set or
+// Delete.
+func (c *Loading[K, V]) run(ctx context.Context, key K, cl *call[V], refresh bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			var zero V
+			cl.value, cl.err = zero, fmt.Errorf("cache: panic: %v", v)
+		}
+		c.mu.Lock()
+		if !cl.stale {
+			delete(c.calls, key)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	start := time.Now()
+	cl.value, cl.err = c.load(ctx, key)
+	c.recordLoad(time.Since(start), cl.err)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cl.stale {
+		return
+	}
+	switch {
+	case cl.err == nil:
+		c.entries.Set(key, &loaded[V]{value: cl.value, loadedAt: time.Now(), delta: time.Since(start)})
+	case c.notFoundTTL > 0 && errors.Is(cl.err, ErrNotFound):
+		c.entries.Set(key, &loaded[V]{err: cl.err, loadedAt: time.Now(), ttl: c.notFoundTTL})
+	case c.errorTTL > 0 && !refresh:
+		c.entries.Set(key, &loaded[V]{err: cl.err, loadedAt: time.Now(), ttl: c.errorTTL})
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Weigher returns the cost of an entry, such as the size of its value in
+// bytes, for caches bounded by total cost rather than entry count.
+type Weigher[K comparable, V any] func(key K, value V) int64
+
+// NewLRU creates a Cache holding up to capacity entries, evicting the least
+// recently used entry when full.
+func NewLRU[K comparable, V any](capacity int) Cache[K, V] {
+	return NewWeightedLRU[K, V](int64(capacity), nil)
+}
+
+// NewWeightedLRU creates a Cache holding entries of a total cost of up to
+// maxCost, as weighed by weigher, evicting the least recently used entries to
+// make room. Entries costing more than maxCost are not cached. A nil weigher
+// weighs every entry 1.
+func NewWeightedLRU[K comparable, V any](maxCost int64, weigher Weigher[K, V]) Cache[K, V] {
+	if weigher == nil {
+		weigher = func(K, V) int64 { return 1 }
+	}
+	return &lru[K, V]{
+		capacity: max(maxCost, 1),
+		weigh:    weigher,
+		items:    make(map[K]*list.Element),
+		ll:       list.New(),
+	}
+}
+
+type lru[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int64
+	cost     int64
+	weigh    Weigher[K, V]
+	items    map[K]*list.Element
+	ll       *list.List
+	counters
+}
+
+type entry[K comparable, V any] struct {
+	key    K
+	value  V
+	weight int64
+}
+
+// Get returns the value of key, marking it as recently used.
+func (c *lru[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	c.recordLookup(ok)
+	if ok {
+		c.ll.MoveToFront(e)
+		return e.Value.(*entry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set caches the value of key, evicting the least recently used entries when full.
+func (c *lru[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	weight := c.weigh(key, value)
+	if weight > c.capacity {
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, weight: weight})
+	c.cost += weight
+	for c.cost > c.capacity {
+		c.remove(c.ll.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// Delete removes key from the cache.
+func (c *lru[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *lru[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Stats returns a snapshot of the statistics of the cache.
+func (c *lru[K, V]) Stats() Stats {
+	return c.snapshot()
+}
+
+func (c *lru[K, V]) remove(e *list.Element) {
+	ent := c.ll.Remove(e).(*entry[K, V])
+	delete(c.items, ent.key)
+	c.cost -= ent.weight
+}
@@ -0,0 +1,76 @@
+// Package promcache exports cache statistics to Prometheus.
+package promcache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/go-kratos/kit/cache"
+)
+
+// Source is a cache reporting its statistics, such as a cache.Cache, a
+// cache.Loading or a cache.Tiered.
+type Source interface {
+	Stats() cache.Stats
+}
+
+// Collector is a prometheus.Collector exporting the statistics of caches,
+// labeled with their names.
+type Collector struct {
+	caches    map[string]Source
+	requests  *prometheus.Desc
+	evictions *prometheus.Desc
+	loads     *prometheus.Desc
+	loadTime  *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector for the caches by name with metrics in the
+// given namespace. The hit ratio and the mean load time are derived from the
+// rates of the counters.
+func NewCollector(namespace string, caches map[string]Source) *Collector {
+	return &Collector{
+		caches: caches,
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "requests_total"),
+			"Number of lookups by result: hit or miss.",
+			[]string{"cache", "result"}, nil,
+		),
+		evictions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+			"Number of evicted entries.",
+			[]string{"cache"}, nil,
+		),
+		loads: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "loads_total"),
+			"Number of loads by result: success or failure.",
+			[]string{"cache", "result"}, nil,
+		),
+		loadTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "load_duration_seconds_total"),
+			"Time spent loading.",
+			[]string{"cache"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requests
+	ch <- c.evictions
+	ch <- c.loads
+	ch <- c.loadTime
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for name, src := range c.caches {
+		s := src.Stats()
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Hits), name, "hit")
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.Misses), name, "miss")
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(s.Evictions), name)
+		ch <- prometheus.MustNewConstMetric(c.loads, prometheus.CounterValue, float64(s.Loads-s.LoadFailures), name, "success")
+		ch <- prometheus.MustNewConstMetric(c.loads, prometheus.CounterValue, float64(s.LoadFailures), name, "failure")
+		ch <- prometheus.MustNewConstMetric(c.loadTime, prometheus.CounterValue, s.TotalLoadTime.Seconds(), name)
+	}
+}
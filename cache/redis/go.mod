@@ -0,0 +1,15 @@
+module github.com/go-kratos/kit/cache/redis
+
+go 1.24.0
+
+replace github.com/go-kratos/kit => ../..
+
+require (
+	github.com/go-kratos/kit v0.0.0
+	github.com/redis/go-redis/v9 v9.7.3
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+)
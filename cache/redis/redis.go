@@ -0,0 +1,95 @@
+// Package redis implements the remote level of tiered caches and the
+// transport of invalidation buses with Redis.
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/go-kratos/kit/cache"
+)
+
+// Remote is a cache.Remote storing values in Redis.
+type Remote struct {
+	client goredis.Cmdable
+}
+
+var _ cache.Remote = (*Remote)(nil)
+
+// NewRemote creates a Remote storing values with client.
+func NewRemote(client goredis.Cmdable) *Remote {
+	return &Remote{client: client}
+}
+
+// Get returns the value of key, or cache.ErrNotFound.
+func (r *Remote) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, cache.ErrNotFound
+	}
+	return v, err
+}
+
+// Set stores the value of key, expiring after ttl.
+func (r *Remote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete removes key.
+func (r *Remote) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+// Transport is a cache.Transport broadcasting invalidations over a Redis
+// pub/sub channel.
+type Transport struct {
+	client  goredis.UniversalClient
+	channel string
+}
+
+var _ cache.Transport = (*Transport)(nil)
+
+// NewTransport creates a Transport publishing to channel with client.
+func NewTransport(client goredis.UniversalClient, channel string) *Transport {
+	return &Transport{client: client, channel: channel}
+}
+
+// Publish sends payload to the subscribers of the channel.
+func (t *Transport) Publish(ctx context.Context, payload []byte) error {
+	return t.client.Publish(ctx, t.channel, payload).Err()
+}
+
+// Subscribe returns the payloads published to the channel until ctx is done.
+// Messages published while the connection is reestablished are lost.
+func (t *Transport) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	ps := t.client.Subscribe(ctx, t.channel)
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, err
+	}
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		defer ps.Close()
+		msgs := ps.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/go-kratos/kit/cache"
+)
+
+func TestTieredLoadsWhenUnreachable(t *testing.T) {
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	c := cache.NewTiered(NewRemote(client), func(ctx context.Context, key string) (string, error) {
+		return key, nil
+	})
+	defer c.Close()
+	if v, err := c.Get(context.Background(), "a"); err != nil || v != "a" {
+		t.Fatalf("expected %v, got %v, %v", "a", v, err)
+	}
+	if _, err := NewRemote(client).Get(context.Background(), "a"); err == nil || errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected a connection error, got %v", err)
+	}
+}
+
+func TestTransportSubscribeUnreachable(t *testing.T) {
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	bus := cache.NewBus(NewTransport(client, "invalidations"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := bus.Run(ctx); err == nil {
+		t.Fatalf("expected a connection error")
+	}
+}
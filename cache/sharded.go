@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// NewSharded creates a Cache spreading its keys by hash over shards created
+// with newShard, so that writers of different keys rarely contend for the
+// same lock. The number of shards is rounded up to a power of two; each shard
+// holds its own share of the entries, e.g. an LRU of a fraction of the total
+// capacity.
+func NewSharded[K comparable, V any](shards int, newShard func() Cache[K, V]) Cache[K, V] {
+	n := 1 << bits.Len(uint(max(shards, 1)-1))
+	c := &sharded[K, V]{seed: maphash.MakeSeed(), shards: make([]Cache[K, V], n), mask: uint64(n - 1)}
+	for i := range c.shards {
+		c.shards[i] = newShard()
+	}
+	return c
+}
+
+type sharded[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []Cache[K, V]
+	mask   uint64
+}
+
+func (c *sharded[K, V]) shard(key K) Cache[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)&c.mask]
+}
+
+// Get returns the value of key, if cached.
+func (c *sharded[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+// Set caches the value of key in its shard.
+func (c *sharded[K, V]) Set(key K, value V) {
+	c.shard(key).Set(key, value)
+}
+
+// Delete removes key from the cache.
+func (c *sharded[K, V]) Delete(key K) {
+	c.shard(key).Delete(key)
+}
+
+// Len returns the number of cached entries of all shards.
+func (c *sharded[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Stats returns the sum of the statistics of all shards.
+func (c *sharded[K, V]) Stats() Stats {
+	var total Stats
+	for _, s := range c.shards {
+		st := s.Stats()
+		total.Hits += st.Hits
+		total.Misses += st.Misses
+		total.Evictions += st.Evictions
+		total.Loads += st.Loads
+		total.LoadFailures += st.LoadFailures
+		total.TotalLoadTime += st.TotalLoadTime
+	}
+	return total
+}
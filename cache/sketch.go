@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// sketch is a count-min sketch estimating the access frequency of keys with
+// 4 rows of saturating counters. Counters are halved once the number of
+// increments reaches the sample size, so that old accesses fade away.
+type sketch[K comparable] struct {
+	seed    maphash.Seed
+	rows    [4][]uint8
+	mask    uint64
+	adds    int
+	samples int
+}
+
+func newSketch[K comparable](capacity int) *sketch[K] {
+	width := 1 << bits.Len(uint(max(capacity, 16)-1))
+	s := &sketch[K]{seed: maphash.MakeSeed(), mask: uint64(width - 1), samples: 10 * max(capacity, 16)}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *sketch[K]) indexes(key K) [4]uint64 {
+	h := maphash.Comparable(s.seed, key)
+	h1, h2 := h&0xffffffff, h>>32
+	var idx [4]uint64
+	for i := range idx {
+		idx[i] = (h1 + uint64(i)*h2) & s.mask
+	}
+	return idx
+}
+
+// add records an access of key.
+func (s *sketch[K]) add(key K) {
+	for i, j := range s.indexes(key) {
+		if s.rows[i][j] < 15 {
+			s.rows[i][j]++
+		}
+	}
+	if s.adds++; s.adds >= s.samples {
+		s.reset()
+	}
+}
+
+// estimate returns the estimated access frequency of key.
+func (s *sketch[K]) estimate(key K) uint8 {
+	n := uint8(15)
+	for i, j := range s.indexes(key) {
+		n = min(n, s.rows[i][j])
+	}
+	return n
+}
+
+func (s *sketch[K]) reset() {
+	for _, row := range s.rows {
+		for j := range row {
+			row[j] /= 2
+		}
+	}
+	s.adds /= 2
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the statistics of a cache.
+type Stats struct {
+	Hits         uint64
+	Misses       uint64
+	Evictions    uint64
+	Loads        uint64
+	LoadFailures uint64
+	// TotalLoadTime is the time spent loading, successfully or not.
+	TotalLoadTime time.Duration
+}
+
+// HitRatio returns the share of lookups that were hits, or 0 without lookups.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// MeanLoadTime returns the mean time spent per load, or 0 without loads.
+func (s Stats) MeanLoadTime() time.Duration {
+	if s.Loads == 0 {
+		return 0
+	}
+	return s.TotalLoadTime / time.Duration(s.Loads)
+}
+
+// counters accumulates the statistics of a cache.
+type counters struct {
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	evictions    atomic.Uint64
+	loads        atomic.Uint64
+	loadFailures atomic.Uint64
+	loadTime     atomic.Int64
+}
+
+func (c *counters) recordLookup(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+func (c *counters) recordLoad(d time.Duration, err error) {
+	c.loads.Add(1)
+	c.loadTime.Add(int64(d))
+	if err != nil {
+		c.loadFailures.Add(1)
+	}
+}
+
+func (c *counters) snapshot() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     c.evictions.Load(),
+		Loads:         c.loads.Load(),
+		LoadFailures:  c.loadFailures.Load(),
+		TotalLoadTime: time.Duration(c.loadTime.Load()),
+	}
+}
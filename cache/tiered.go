@@ -0,0 +1,227 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotFound is returned by Remote stores and loaders for missing keys.
+var ErrNotFound = errors.New("cache: not found")
+
+// Remote is a store shared by the replicas of a service, such as Redis.
+type Remote interface {
+	// Get returns the value of key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores the value of key, expiring after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key.
+	Delete(ctx context.Context, key string) error
+}
+
+// TieredOption is tiered cache option.
+type TieredOption func(*tieredOptions)
+
+type tieredOptions struct {
+	localCapacity int
+	localTTL      time.Duration
+	remoteTTL     time.Duration
+	codec         string
+}
+
+// WithLocalCapacity sets the maximum number of entries of the local level,
+// evicting the least recently used ones. The default is 1024.
+func WithLocalCapacity(n int) TieredOption {
+	return func(o *tieredOptions) {
+		o.localCapacity = n
+	}
+}
+
+// WithLocalTTL sets the TTL of the local level, one minute by default. It
+// bounds how long a replica may serve a value changed by another one.
+func WithLocalTTL(ttl time.Duration) TieredOption {
+	return func(o *tieredOptions) {
+		o.localTTL = ttl
+	}
+}
+
+// WithRemoteTTL sets the TTL of the remote level, ten minutes by default.
+// Zero stores remote values without expiry.
+func WithRemoteTTL(ttl time.Duration) TieredOption {
+	return func(o *tieredOptions) {
+		o.remoteTTL = ttl
+	}
+}
+
+// WithCodec sets the name of the registered codec serializing the values of
+// the remote level, "json" by default.
+func WithCodec(name string) TieredOption {
+	return func(o *tieredOptions) {
+		o.codec = name
+	}
+}
+
+// TieredStats holds the statistics of each level of a Tiered cache.
+type TieredStats struct {
+	Local  Stats
+	Remote Stats
+	// RemoteErrors counts the failed calls to the remote level.
+	RemoteErrors uint64
+}
+
+// Tiered is a read-through cache with an in-process level in front of a
+// Remote level, loading the values missing from both with a loader.
+type Tiered[V any] struct {
+	local     Cache[string, tieredEntry[V]]
+	localTTL  time.Duration
+	remote    Remote
+	codec     Codec
+	load      func(ctx context.Context, key string) (V, error)
+	remoteTTL time.Duration
+	counters
+
+	localStats                             counters
+	remoteHits, remoteMisses, remoteErrors atomic.Uint64
+}
+
+type tieredEntry[V any] struct {
+	value  V
+	expiry time.Time
+}
+
+// NewTiered creates a Tiered cache over remote, loading missing values with
+// load. The local level is an LRU cache whose TTL is capped at half of an
+// expiring remote TTL, so that the local level never outlives the remote one.
+// It panics if the codec is not registered.
+func NewTiered[V any](remote Remote, load func(ctx context.Context, key string) (V, error), opts ...TieredOption) *Tiered[V] {
+	o := tieredOptions{localCapacity: 1024, localTTL: time.Minute, remoteTTL: 10 * time.Minute, codec: "json"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	codec := GetCodec(o.codec)
+	if codec == nil {
+		panic("cache: codec " + o.codec + " is not registered")
+	}
+	localTTL := o.localTTL
+	if o.remoteTTL > 0 {
+		localTTL = min(localTTL, o.remoteTTL/2)
+	}
+	return &Tiered[V]{
+		local:     NewLRU[string, tieredEntry[V]](o.localCapacity),
+		localTTL:  localTTL,
+		remote:    remote,
+		codec:     codec,
+		load:      load,
+		remoteTTL: o.remoteTTL,
+	}
+}
+
+// Get returns the value of key from the local level, the remote level or the
+// loader, filling the levels it was missing from. Remote failures are
+// counted and fall through to the loader.
+func (c *Tiered[V]) Get(ctx context.Context, key string) (V, error) {
+	if v, ok := c.getLocal(key); ok {
+		c.recordLookup(true)
+		return v, nil
+	}
+	var v V
+	data, err := c.remote.Get(ctx, key)
+	if err == nil {
+		if err = c.codec.Unmarshal(data, &v); err == nil {
+			c.remoteHits.Add(1)
+			c.recordLookup(true)
+			c.setLocal(key, v)
+			return v, nil
+		}
+	}
+	if errors.Is(err, ErrNotFound) {
+		c.remoteMisses.Add(1)
+	} else {
+		c.remoteErrors.Add(1)
+	}
+	c.recordLookup(false)
+	start := time.Now()
+	v, err = c.load(ctx, key)
+	c.recordLoad(time.Since(start), err)
+	if err != nil {
+		return v, err
+	}
+	c.set(ctx, key, v)
+	return v, nil
+}
+
+// Set stores the value of key in both levels. A failure to store it remotely
+// is returned after the local level is updated.
+func (c *Tiered[V]) Set(ctx context.Context, key string, value V) error {
+	return c.set(ctx, key, value)
+}
+
+func (c *Tiered[V]) set(ctx context.Context, key string, value V) error {
+	c.setLocal(key, value)
+	data, err := c.codec.Marshal(value)
+	if err == nil {
+		err = c.remote.Set(ctx, key, data, c.remoteTTL)
+	}
+	if err != nil {
+		c.remoteErrors.Add(1)
+	}
+	return err
+}
+
+// getLocal returns the value of key from the local level, if not expired.
+func (c *Tiered[V]) getLocal(key string) (V, bool) {
+	e, ok := c.local.Get(key)
+	if ok && time.Now().Before(e.expiry) {
+		c.localStats.recordLookup(true)
+		return e.value, true
+	}
+	if ok {
+		c.local.Delete(key)
+	}
+	c.localStats.recordLookup(false)
+	var zero V
+	return zero, false
+}
+
+func (c *Tiered[V]) setLocal(key string, value V) {
+	c.local.Set(key, tieredEntry[V]{value: value, expiry: time.Now().Add(c.localTTL)})
+}
+
+// Delete removes key from both levels. Other replicas may serve their local
+// value until it expires, unless their Local level is registered with a Bus.
+func (c *Tiered[V]) Delete(ctx context.Context, key string) error {
+	c.local.Delete(key)
+	return c.remote.Delete(ctx, key)
+}
+
+// Local returns the local level of the cache. Registering it with a Bus
+// drops the local values of keys invalidated by other replicas.
+func (c *Tiered[V]) Local() Deleter {
+	return c.local
+}
+
+// Stats returns a snapshot of the statistics of the cache as a whole: hits
+// of either level, misses of both and the evictions of the local level.
+func (c *Tiered[V]) Stats() Stats {
+	s := c.snapshot()
+	s.Evictions = c.local.Stats().Evictions
+	return s
+}
+
+// LevelStats returns a snapshot of the statistics of each level.
+func (c *Tiered[V]) LevelStats() TieredStats {
+	local := c.localStats.snapshot()
+	local.Evictions = c.local.Stats().Evictions
+	return TieredStats{
+		Local:        local,
+		Remote:       Stats{Hits: c.remoteHits.Load(), Misses: c.remoteMisses.Load()},
+		RemoteErrors: c.remoteErrors.Load(),
+	}
+}
+
+// Close releases the resources of the cache. The local level needs no
+// background sweeper, so it is a no-op kept for compatibility.
+func (c *Tiered[V]) Close() error {
+	return nil
+}
@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// TinyLFUOption is TinyLFU cache option.
+type TinyLFUOption func(*tinyLFUOptions)
+
+type tinyLFUOptions struct {
+	windowRatio float64
+}
+
+// WithWindowRatio sets the share of the capacity given to the admission
+// window, which holds new entries until they compete for the main space.
+// A larger window favors recency, a smaller one frequency. The default is 0.01.
+func WithWindowRatio(ratio float64) TinyLFUOption {
+	return func(o *tinyLFUOptions) {
+		o.windowRatio = ratio
+	}
+}
+
+// NewTinyLFU creates a Cache holding up to capacity entries with the W-TinyLFU
+// policy. New entries enter a small LRU window; when it overflows, its least
+// recently used entry is admitted into the main segmented LRU only if it is
+// accessed more frequently than the entry it would evict, as estimated by a
+// count-min sketch of recent accesses. Hot keys therefore survive scans of
+// one-off keys that would flush a plain LRU.
+func NewTinyLFU[K comparable, V any](capacity int, opts ...TinyLFUOption) Cache[K, V] {
+	return NewWeightedTinyLFU[K, V](int64(capacity), nil, opts...)
+}
+
+// NewWeightedTinyLFU creates a Cache holding entries of a total cost of up to
+// maxCost, as weighed by weigher, with the W-TinyLFU policy. An entry leaving
+// the window is admitted only if it is accessed more frequently than each of
+// the entries it evicts to make room. Entries costing more than the main
+// space, which is maxCost minus the window, are not cached. A nil weigher
+// weighs every entry 1.
+func NewWeightedTinyLFU[K comparable, V any](maxCost int64, weigher Weigher[K, V], opts ...TinyLFUOption) Cache[K, V] {
+	o := tinyLFUOptions{windowRatio: 0.01}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if weigher == nil {
+		weigher = func(K, V) int64 { return 1 }
+	}
+	maxCost = max(maxCost, 2)
+	windowCap := min(max(int64(float64(maxCost)*o.windowRatio), 1), maxCost-1)
+	mainCap := maxCost - windowCap
+	return &tinyLFU[K, V]{
+		// The sketch is sized for entries of cost 1, within reason.
+		sketch:    newSketch[K](int(min(maxCost, 1<<20))),
+		weigh:     weigher,
+		items:     make(map[K]*list.Element),
+		window:    &segment{List: list.New(), capacity: windowCap},
+		probation: &segment{List: list.New()},
+		protected: &segment{List: list.New(), capacity: max(mainCap*8/10, 1)},
+		mainCap:   mainCap,
+	}
+}
+
+// tinyLFU splits the main space into a probation segment, holding entries
+// admitted from the window, and a protected segment, holding entries accessed
+// again while on probation.
+type tinyLFU[K comparable, V any] struct {
+	mu        sync.Mutex
+	sketch    *sketch[K]
+	weigh     Weigher[K, V]
+	items     map[K]*list.Element
+	window    *segment
+	probation *segment
+	protected *segment
+	mainCap   int64
+	counters
+}
+
+// segment is an LRU list of entries with their total cost.
+type segment struct {
+	*list.List
+	cost     int64
+	capacity int64
+}
+
+type lfuEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	weight  int64
+	segment *segment
+}
+
+// Get returns the value of key, recording the access.
+func (c *tinyLFU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sketch.add(key)
+	e, ok := c.items[key]
+	c.recordLookup(ok)
+	if ok {
+		c.touch(e)
+		return e.Value.(*lfuEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set caches the value of key. A new entry may be evicted again without being
+// admitted if it is not accessed often enough.
+func (c *tinyLFU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sketch.add(key)
+	weight := c.weigh(key, value)
+	if e, ok := c.items[key]; ok {
+		ent := e.Value.(*lfuEntry[K, V])
+		if weight == ent.weight {
+			ent.value = value
+			c.touch(e)
+			return
+		}
+		c.remove(e)
+	}
+	if weight > c.mainCap {
+		return
+	}
+	c.push(&lfuEntry[K, V]{key: key, value: value, weight: weight}, c.window)
+	for c.window.cost > c.window.capacity {
+		c.admit(c.window.Back())
+	}
+}
+
+// Delete removes key from the cache.
+func (c *tinyLFU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *tinyLFU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Stats returns a snapshot of the statistics of the cache. Entries rejected
+// by the admission policy count as evictions.
+func (c *tinyLFU[K, V]) Stats() Stats {
+	return c.snapshot()
+}
+
+// touch moves an accessed entry to the front of its segment, promoting it to
+// the protected segment if it is on probation.
+func (c *tinyLFU[K, V]) touch(e *list.Element) {
+	ent := e.Value.(*lfuEntry[K, V])
+	if ent.segment != c.probation {
+		ent.segment.MoveToFront(e)
+		return
+	}
+	c.move(e, c.protected)
+	for c.protected.cost > c.protected.capacity && c.protected.Len() > 1 {
+		c.move(c.protected.Back(), c.probation)
+	}
+}
+
+// admit moves the candidate evicted from the window into the probation
+// segment, evicting the main victims it is more frequent than to make room.
+// The candidate is evicted instead as soon as a victim is as frequent.
+func (c *tinyLFU[K, V]) admit(candidate *list.Element) {
+	ent := candidate.Value.(*lfuEntry[K, V])
+	freq := c.sketch.estimate(ent.key)
+	for c.probation.cost+c.protected.cost+ent.weight > c.mainCap {
+		victim := c.probation.Back()
+		if victim == nil {
+			victim = c.protected.Back()
+		}
+		if victim == nil || freq <= c.sketch.estimate(victim.Value.(*lfuEntry[K, V]).key) {
+			c.remove(candidate)
+			c.evictions.Add(1)
+			return
+		}
+		c.remove(victim)
+		c.evictions.Add(1)
+	}
+	c.move(candidate, c.probation)
+}
+
+// push adds ent to the front of seg.
+func (c *tinyLFU[K, V]) push(ent *lfuEntry[K, V], seg *segment) {
+	ent.segment = seg
+	seg.cost += ent.weight
+	c.items[ent.key] = seg.PushFront(ent)
+}
+
+// move moves e to the front of seg.
+func (c *tinyLFU[K, V]) move(e *list.Element, seg *segment) {
+	c.remove(e)
+	c.push(e.Value.(*lfuEntry[K, V]), seg)
+}
+
+func (c *tinyLFU[K, V]) remove(e *list.Element) {
+	ent := e.Value.(*lfuEntry[K, V])
+	ent.segment.Remove(e)
+	ent.segment.cost -= ent.weight
+	delete(c.items, ent.key)
+}
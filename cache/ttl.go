@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// TTLOption is TTL cache option.
+type TTLOption func(*ttlOptions)
+
+type ttlOptions struct {
+	jitter        float64
+	sweepInterval time.Duration
+}
+
+// WithJitter shortens the TTL of each entry by a random share of up to jitter,
+// e.g. 0.1 for up to 10%, so that entries set together do not all expire at
+// once and hammer the backend when they are reloaded.
+func WithJitter(jitter float64) TTLOption {
+	return func(o *ttlOptions) {
+		o.jitter = jitter
+	}
+}
+
+// WithSweepInterval sets the interval at which expired entries are removed in
+// the background. The default is one minute; zero disables sweeping, leaving
+// expired entries in memory until they are accessed or overwritten.
+func WithSweepInterval(interval time.Duration) TTLOption {
+	return func(o *ttlOptions) {
+		o.sweepInterval = interval
+	}
+}
+
+// TTL is a Cache whose entries expire after a time to live.
+type TTL[K comparable, V any] struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	jitter float64
+	items  map[K]ttlEntry[V]
+	once   sync.Once
+	done   chan struct{}
+	counters
+}
+
+type ttlEntry[V any] struct {
+	value  V
+	expiry time.Time
+}
+
+var _ Cache[string, any] = (*TTL[string, any])(nil)
+
+// NewTTL creates a TTL cache whose entries expire after ttl. Close must be
+// called to stop the background sweeper when the cache is no longer used.
+func NewTTL[K comparable, V any](ttl time.Duration, opts ...TTLOption) *TTL[K, V] {
+	o := ttlOptions{sweepInterval: time.Minute}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := &TTL[K, V]{
+		ttl:    ttl,
+		jitter: o.jitter,
+		items:  make(map[K]ttlEntry[V]),
+		done:   make(chan struct{}),
+	}
+	if o.sweepInterval > 0 {
+		go c.sweeper(o.sweepInterval)
+	}
+	return c
+}
+
+// Get returns the value of key, if cached and not expired.
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	ok = ok && time.Now().Before(e.expiry)
+	c.recordLookup(ok)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set caches the value of key with the default TTL.
+func (c *TTL[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL caches the value of key with the given TTL, shortened by the
+// configured jitter.
+func (c *TTL[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	if c.jitter > 0 {
+		ttl -= time.Duration(rand.Float64() * c.jitter * float64(ttl))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = ttlEntry[V]{value: value, expiry: time.Now().Add(ttl)}
+}
+
+// Delete removes key from the cache.
+func (c *TTL[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Len returns the number of cached entries, including expired entries that
+// have not been swept yet.
+func (c *TTL[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Stats returns a snapshot of the statistics of the cache. Expired entries
+// count as evictions once swept.
+func (c *TTL[K, V]) Stats() Stats {
+	return c.snapshot()
+}
+
+// Close stops the background sweeper. The cache remains usable.
+func (c *TTL[K, V]) Close() error {
+	c.once.Do(func() { close(c.done) })
+	return nil
+}
+
+func (c *TTL[K, V]) sweeper(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			c.sweep()
+		}
+	}
+}
+
+// sweep removes the expired entries.
+func (c *TTL[K, V]) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.items {
+		if !now.Before(e.expiry) {
+			delete(c.items, k)
+			c.evictions.Add(1)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kit/retry"
+)
+
+// ErrQueueFull is returned when a write-behind cache has too many pending writes.
+var ErrQueueFull = errors.New("cache: write queue full")
+
+// ErrClosed is returned when writing to a closed write-behind cache.
+var ErrClosed = errors.New("cache: write-behind cache closed")
+
+// WriteThrough is a cache writing values to a backing store before caching
+// them, so that the cache never holds values the store does not.
+type WriteThrough[K comparable, V any] struct {
+	cache Cache[K, V]
+	write func(ctx context.Context, key K, value V) error
+}
+
+// NewWriteThrough creates a WriteThrough cache over c, writing values with write.
+func NewWriteThrough[K comparable, V any](c Cache[K, V], write func(ctx context.Context, key K, value V) error) *WriteThrough[K, V] {
+	return &WriteThrough[K, V]{cache: c, write: write}
+}
+
+// Get returns the value of key, if cached.
+func (c *WriteThrough[K, V]) Get(key K) (V, bool) {
+	return c.cache.Get(key)
+}
+
+// Set writes the value of key to the store and caches it if the write
+// succeeds. On failure, the cached value of key is removed, as the store may
+// or may not hold the new value.
+func (c *WriteThrough[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := c.write(ctx, key, value); err != nil {
+		c.cache.Delete(key)
+		return err
+	}
+	c.cache.Set(key, value)
+	return nil
+}
+
+// Delete removes key from the cache, but not from the store.
+func (c *WriteThrough[K, V]) Delete(key K) {
+	c.cache.Delete(key)
+}
+
+// Len returns the number of cached entries.
+func (c *WriteThrough[K, V]) Len() int {
+	return c.cache.Len()
+}
+
+// Stats returns a snapshot of the statistics of the cache.
+func (c *WriteThrough[K, V]) Stats() Stats {
+	return c.cache.Stats()
+}
+
+// WriteBehindOption is write-behind cache option.
+type WriteBehindOption func(*writeBehindOptions)
+
+type writeBehindOptions struct {
+	queueSize     int
+	batchSize     int
+	flushInterval time.Duration
+	retry         *retry.Retry
+	onError       func(err error)
+}
+
+// WithQueueSize sets the maximum number of keys waiting to be written, 1024
+// by default. Set fails with ErrQueueFull beyond it.
+func WithQueueSize(n int) WriteBehindOption {
+	return func(o *writeBehindOptions) {
+		o.queueSize = n
+	}
+}
+
+// WithBatchSize sets the number of pending keys triggering a flush before the
+// flush interval elapses, 100 by default.
+func WithBatchSize(n int) WriteBehindOption {
+	return func(o *writeBehindOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithFlushInterval sets the interval at which pending writes are flushed,
+// one second by default.
+func WithFlushInterval(d time.Duration) WriteBehindOption {
+	return func(o *writeBehindOptions) {
+		o.flushInterval = d
+	}
+}
+
+// WithFlushRetry sets the retryer of failed flushes, 3 attempts with the
+// default backoff by default.
+func WithFlushRetry(r *retry.Retry) WriteBehindOption {
+	return func(o *writeBehindOptions) {
+		o.retry = r
+	}
+}
+
+// WithOnFlushError sets a hook invoked with the error of a flush that failed
+// all its attempts. The writes of the batch are dropped.
+func WithOnFlushError(fn func(err error)) WriteBehindOption {
+	return func(o *writeBehindOptions) {
+		o.onError = fn
+	}
+}
+
+// WriteBehind is a cache caching values at once and writing them to a
+// backing store in the background, in batches. Consecutive writes of a key
+// waiting to be flushed are coalesced into the last one.
+type WriteBehind[K comparable, V any] struct {
+	cache Cache[K, V]
+	flush func(ctx context.Context, batch map[K]V) error
+	opts  writeBehindOptions
+
+	mu      sync.Mutex
+	pending map[K]V
+	closed  bool
+	// flushing serializes the flushes, so that batches are written in order.
+	flushing sync.Mutex
+	kick     chan struct{}
+	done     chan struct{}
+	stopped  chan struct{}
+	once     sync.Once
+}
+
+// NewWriteBehind creates a WriteBehind cache over c, writing batches of
+// values with flush. Close must be called to flush the pending writes and
+// stop the background flusher.
+func NewWriteBehind[K comparable, V any](c Cache[K, V], flush func(ctx context.Context, batch map[K]V) error, opts ...WriteBehindOption) *WriteBehind[K, V] {
+	o := writeBehindOptions{queueSize: 1024, batchSize: 100, flushInterval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.retry == nil {
+		o.retry = retry.New(3)
+	}
+	w := &WriteBehind[K, V]{
+		cache:   c,
+		flush:   flush,
+		opts:    o,
+		pending: make(map[K]V),
+		kick:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.flusher()
+	return w
+}
+
+// Get returns the value of key, if cached.
+func (c *WriteBehind[K, V]) Get(key K) (V, bool) {
+	return c.cache.Get(key)
+}
+
+// Set caches the value of key and queues it to be written. It fails with
+// ErrQueueFull, without caching the value, if too many keys are pending, and
+// with ErrClosed once Close was called. The cache is updated together with the
+// queue, so concurrent writes of a key leave the same value in both.
+func (c *WriteBehind[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	if _, ok := c.pending[key]; !ok && len(c.pending) >= c.opts.queueSize {
+		c.mu.Unlock()
+		return ErrQueueFull
+	}
+	c.pending[key] = value
+	c.cache.Set(key, value)
+	n := len(c.pending)
+	c.mu.Unlock()
+	if n >= c.opts.batchSize {
+		select {
+		case c.kick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Delete removes key from the cache. A pending write of key is still flushed.
+func (c *WriteBehind[K, V]) Delete(key K) {
+	c.cache.Delete(key)
+}
+
+// Len returns the number of cached entries.
+func (c *WriteBehind[K, V]) Len() int {
+	return c.cache.Len()
+}
+
+// Stats returns a snapshot of the statistics of the cache.
+func (c *WriteBehind[K, V]) Stats() Stats {
+	return c.cache.Stats()
+}
+
+// Flush writes the pending values now, retrying failures until ctx is done.
+func (c *WriteBehind[K, V]) Flush(ctx context.Context) error {
+	c.flushing.Lock()
+	defer c.flushing.Unlock()
+	c.mu.Lock()
+	batch := c.pending
+	if len(batch) == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	c.pending = make(map[K]V, len(batch))
+	c.mu.Unlock()
+	err := c.opts.retry.Do(ctx, func(ctx context.Context) error {
+		return c.flush(ctx, maps.Clone(batch))
+	})
+	if err != nil && c.opts.onError != nil {
+		c.opts.onError(err)
+	}
+	return err
+}
+
+// Close stops the background flusher and flushes the pending values until
+// ctx is done. Set fails with ErrClosed afterwards.
+func (c *WriteBehind[K, V]) Close(ctx context.Context) error {
+	c.once.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+		close(c.done)
+	})
+	select {
+	case <-c.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return c.Flush(ctx)
+}
+
+func (c *WriteBehind[K, V]) flusher() {
+	defer close(c.stopped)
+	t := time.NewTicker(c.opts.flushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+		case <-c.kick:
+		}
+		c.Flush(context.Background())
+	}
+}
@@ -0,0 +1,155 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is the error a task panicking with Value fails with.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("group: panic: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the value of the panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Option is group option.
+type Option func(*Group)
+
+// WithCollectAll makes Wait return all the errors of the tasks joined with
+// errors.Join, rather than the first one. The tasks are not canceled on
+// failure then: the context is only canceled when Wait returns.
+func WithCollectAll() Option {
+	return func(g *Group) {
+		g.collectAll = true
+	}
+}
+
+// Group runs tasks in goroutines and waits for them, like errgroup, except
+// that panics are recovered into a PanicError and that all the errors may be
+// collected. The zero value is a Group without context, limit or options.
+type Group struct {
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	collectAll bool
+	wg         sync.WaitGroup
+	sem        chan struct{}
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// WithContext creates a Group whose tasks run with a context derived from
+// ctx, canceled when a task fails or when Wait returns.
+func WithContext(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{ctx: ctx, cancel: cancel}
+	for _, o := range opts {
+		o(g)
+	}
+	return g, ctx
+}
+
+// SetLimit limits the number of tasks running at once to n, or removes the
+// limit if n is negative. It must not be called while tasks are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("group: modify limit while %v tasks are still running", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs fn with the context of the group in a new goroutine, blocking until
+// the limit allows it.
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn)
+}
+
+// TryGo runs fn in a new goroutine if the limit allows it now, reporting
+// whether it did.
+func (g *Group) TryGo(fn func(ctx context.Context) error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+// Wait waits for all the tasks and returns the first error, or all of them
+// joined with WithCollectAll.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var err error
+	if g.collectAll {
+		err = errors.Join(g.errs...)
+	} else if len(g.errs) > 0 {
+		err = g.errs[0]
+	}
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+	return err
+}
+
+func (g *Group) start(fn func(ctx context.Context) error) {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := run(ctx, fn); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+	if !g.collectAll && len(g.errs) == 1 && g.cancel != nil {
+		g.cancel(err)
+	}
+}
+
+func run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,99 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupFirstError(t *testing.T) {
+	errFirst := errors.New("first")
+	g, ctx := WithContext(context.Background())
+	g.Go(func(ctx context.Context) error { return errFirst })
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := g.Wait(); !errors.Is(err, errFirst) {
+		t.Fatalf("expected %v, got %v", errFirst, err)
+	}
+	if !errors.Is(context.Cause(ctx), errFirst) {
+		t.Fatalf("expected the context to be canceled with %v, got %v", errFirst, context.Cause(ctx))
+	}
+}
+
+func TestGroupCollectAll(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	g, ctx := WithContext(context.Background(), WithCollectAll())
+	g.Go(func(ctx context.Context) error { return errA })
+	g.Go(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Errorf("expected the context not to be canceled")
+		}
+		return errB
+	})
+	err := g.Wait()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected both errors, got %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("expected the context to be canceled after Wait")
+	}
+}
+
+func TestGroupRecoversPanics(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) error { panic("boom") })
+	var pe *PanicError
+	if err := g.Wait(); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Fatalf("expected a panic error, got %v", err)
+	}
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(2)
+	var running, peak atomic.Int32
+	for range 10 {
+		g.Go(func(ctx context.Context) error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("expected at most %v tasks at once, got %v", 2, peak.Load())
+	}
+}
+
+func TestGroupTryGo(t *testing.T) {
+	var g Group
+	g.SetLimit(1)
+	release := make(chan struct{})
+	g.Go(func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	if g.TryGo(func(ctx context.Context) error { return nil }) {
+		t.Fatalf("expected TryGo to respect the limit")
+	}
+	close(release)
+	g.Wait()
+	if !g.TryGo(func(ctx context.Context) error { return nil }) {
+		t.Fatalf("expected TryGo to run the task")
+	}
+	g.Wait()
+}
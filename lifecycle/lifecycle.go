@@ -0,0 +1,164 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook is the startup and shutdown of a component. Either function may be nil.
+type Hook struct {
+	// Name identifies the hook in errors.
+	Name string
+	// OnStart starts the component. It must not block serving, but start a
+	// goroutine doing so.
+	OnStart func(ctx context.Context) error
+	// OnStop stops the component, releasing its resources.
+	OnStop func(ctx context.Context) error
+	// StartTimeout and StopTimeout override the timeouts of the lifecycle.
+	StartTimeout time.Duration
+	StopTimeout  time.Duration
+}
+
+// TimeoutError is returned when a hook does not return within its timeout.
+type TimeoutError struct {
+	Hook    string
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("lifecycle: %s hook %q timed out after %v", e.Phase, e.Hook, e.Timeout)
+}
+
+// Option is lifecycle option.
+type Option func(*Lifecycle)
+
+// WithSignals sets the signals triggering the shutdown, SIGINT and SIGTERM by default.
+func WithSignals(sigs ...os.Signal) Option {
+	return func(l *Lifecycle) {
+		l.signals = sigs
+	}
+}
+
+// WithStartTimeout sets the default timeout of start hooks, 15s by default.
+func WithStartTimeout(d time.Duration) Option {
+	return func(l *Lifecycle) {
+		l.startTimeout = d
+	}
+}
+
+// WithStopTimeout sets the default timeout of stop hooks, 15s by default.
+func WithStopTimeout(d time.Duration) Option {
+	return func(l *Lifecycle) {
+		l.stopTimeout = d
+	}
+}
+
+// Lifecycle starts components in the order their hooks are appended and
+// stops them in reverse order.
+type Lifecycle struct {
+	signals      []os.Signal
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+
+	mu    sync.Mutex
+	hooks []Hook
+}
+
+// New creates a Lifecycle.
+func New(opts ...Option) *Lifecycle {
+	l := &Lifecycle{
+		signals:      []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		startTimeout: 15 * time.Second,
+		stopTimeout:  15 * time.Second,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Append registers h after the hooks already registered.
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// Run starts the components, waits until ctx is done or a signal is received,
+// then stops them in reverse order. If a component fails to start, the
+// components already started are stopped and the error is returned. The
+// signals are handled from the start, so a signal received while starting
+// stops the components started so far once the current start hook returns.
+// The errors of the stop hooks are returned joined, a hook exceeding its
+// timeout failing with a TimeoutError.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := append([]Hook(nil), l.hooks...)
+	l.mu.Unlock()
+
+	ctx, stop := signal.NotifyContext(ctx, l.signals...)
+	defer stop()
+	for i, h := range hooks {
+		if err := call(ctx, h.Name, "start", h.OnStart, orDefault(h.StartTimeout, l.startTimeout)); err != nil {
+			return errors.Join(err, l.stop(hooks[:i]))
+		}
+		if ctx.Err() != nil {
+			return l.stop(hooks[:i+1])
+		}
+	}
+	<-ctx.Done()
+	return l.stop(hooks)
+}
+
+// stop calls the stop hooks in reverse order.
+func (l *Lifecycle) stop(hooks []Hook) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if err := call(context.Background(), h.Name, "stop", h.OnStop, orDefault(h.StopTimeout, l.stopTimeout)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// call calls fn with a context canceled after timeout, returning a
+// TimeoutError without waiting longer if fn does not return in time. An error
+// returned by fn after the timeout is reported as a TimeoutError as well.
+func call(ctx context.Context, name, phase string, fn func(ctx context.Context) error, timeout time.Duration) error {
+	if fn == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil && ctx.Err() != nil {
+			return &TimeoutError{Hook: name, Phase: phase, Timeout: timeout}
+		}
+		if err != nil {
+			return fmt.Errorf("lifecycle: %s hook %q: %w", phase, name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return &TimeoutError{Hook: name, Phase: phase, Timeout: timeout}
+	}
+}
+
+func orDefault(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,93 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunOrder(t *testing.T) {
+	var calls []string
+	l := New()
+	for _, name := range []string{"db", "cache", "server"} {
+		l.Append(Hook{
+			Name:    name,
+			OnStart: func(ctx context.Context) error { calls = append(calls, "start "+name); return nil },
+			OnStop:  func(ctx context.Context) error { calls = append(calls, "stop "+name); return nil },
+		})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Run(ctx); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	want := []string{"start db", "start cache", "start server", "stop server", "stop cache", "stop db"}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestRunStartFailure(t *testing.T) {
+	errStart := errors.New("start failed")
+	var stopped []string
+	l := New()
+	l.Append(Hook{Name: "db", OnStop: func(ctx context.Context) error { stopped = append(stopped, "db"); return nil }})
+	l.Append(Hook{Name: "server", OnStart: func(ctx context.Context) error { return errStart },
+		OnStop: func(ctx context.Context) error { stopped = append(stopped, "server"); return nil }})
+	if err := l.Run(context.Background()); !errors.Is(err, errStart) {
+		t.Fatalf("expected %v, got %v", errStart, err)
+	}
+	if !slices.Equal(stopped, []string{"db"}) {
+		t.Fatalf("expected the started hooks to be stopped, got %v", stopped)
+	}
+}
+
+func TestRunSignalDuringStart(t *testing.T) {
+	var calls []string
+	l := New(WithSignals(syscall.SIGUSR2))
+	l.Append(Hook{
+		Name: "db",
+		OnStart: func(ctx context.Context) error {
+			calls = append(calls, "start db")
+			syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error { calls = append(calls, "stop db"); return nil },
+	})
+	l.Append(Hook{
+		Name:    "server",
+		OnStart: func(ctx context.Context) error { calls = append(calls, "start server"); return nil },
+	})
+	if err := l.Run(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if want := []string{"start db", "stop db"}; !slices.Equal(calls, want) {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestRunStopTimeout(t *testing.T) {
+	l := New(WithSignals(syscall.SIGUSR1), WithStopTimeout(10*time.Millisecond))
+	l.Append(Hook{Name: "stuck", OnStop: func(ctx context.Context) error { select {} }})
+	l.Append(Hook{Name: "slow", OnStop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, StopTimeout: 5 * time.Millisecond})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	}()
+	err := l.Run(context.Background())
+	var te *TimeoutError
+	if !errors.As(err, &te) || te.Hook != "slow" || te.Phase != "stop" {
+		t.Fatalf("expected the slow hook to time out first, got %v", err)
+	}
+	var errs interface{ Unwrap() []error }
+	if !errors.As(err, &errs) || len(errs.Unwrap()) != 2 {
+		t.Fatalf("expected both hooks to time out, got %v", err)
+	}
+}
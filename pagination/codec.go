@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// TokenCodec converts a cursor state of type T to an opaque page token and back.
+type TokenCodec[T any] interface {
+	// Encode encodes the value into a page token.
+	Encode(T) (string, error)
+	// Decode decodes the value from a page token. The zero value is
+	// returned for an empty token.
+	Decode(string) (T, error)
+}
+
+// ContextTokenCodec is a TokenCodec whose tokens can depend on the request
+// context, as with WithTokenAudience.
+type ContextTokenCodec[T any] interface {
+	TokenCodec[T]
+	// EncodeContext encodes the value into a page token for the context.
+	EncodeContext(context.Context, T) (string, error)
+	// DecodeContext decodes the value from a page token for the context.
+	DecodeContext(context.Context, string) (T, error)
+}
+
+// Validator is implemented by token payloads that can check themselves
+// after decoding.
+type Validator interface {
+	Validate() error
+}
+
+// NewTokenCodec creates a TokenCodec marshaling values of type T as JSON.
+// The token options are applied to the generated page tokens; Encode and
+// Decode use context.Background.
+func NewTokenCodec[T any](opts ...TokenOption) ContextTokenCodec[T] {
+	return &jsonCodec[T]{tokens: newTokenGenerator(opts...)}
+}
+
+type jsonCodec[T any] struct {
+	tokens *tokenGenerator
+}
+
+// Encode encodes the value into a page token.
+func (c *jsonCodec[T]) Encode(v T) (string, error) {
+	return c.EncodeContext(context.Background(), v)
+}
+
+// Decode decodes the value from a page token.
+func (c *jsonCodec[T]) Decode(token string) (T, error) {
+	return c.DecodeContext(context.Background(), token)
+}
+
+// EncodeContext encodes the value into a page token for the context.
+func (c *jsonCodec[T]) EncodeContext(ctx context.Context, v T) (string, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return c.tokens.encode(ctx, bs)
+}
+
+// DecodeContext decodes the value from a page token for the context.
+// Payloads implementing Validator are validated, and any failure is
+// reported as ErrInvalidToken.
+func (c *jsonCodec[T]) DecodeContext(ctx context.Context, token string) (T, error) {
+	var v T
+	if token == "" {
+		return v, nil
+	}
+	payload, err := c.tokens.decode(ctx, token)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return v, ErrInvalidToken
+	}
+	if vv, ok := any(&v).(Validator); ok && vv.Validate() != nil {
+		return v, ErrInvalidToken
+	}
+	return v, nil
+}
@@ -0,0 +1,65 @@
+package pagination
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config configures a paginator, e.g. when loaded from a config file.
+type Config struct {
+	DefaultPage int32 `json:"default_page" yaml:"default_page"`
+	DefaultSize int32 `json:"default_size" yaml:"default_size"`
+	MaxSize     int32 `json:"max_size" yaml:"max_size"`
+	AllowAll    bool  `json:"allow_all" yaml:"allow_all"`
+}
+
+func (c Config) options() []Option {
+	opts := []Option{WithDefaultPage(c.DefaultPage), WithDefaultSize(c.DefaultSize), WithMaxSize(c.MaxSize)}
+	if c.AllowAll {
+		opts = append(opts, WithAllowUnpaginated())
+	}
+	return opts
+}
+
+// NewPaginatorFromConfig creates a Paginator from cfg. Unset fields fall back
+// to page 1 and size 20 without a maximum size.
+func NewPaginatorFromConfig(cfg Config) Paginator {
+	return NewPaginator(1, 20, cfg.options()...)
+}
+
+// FromEnv overrides the fields of c with the environment variables
+// <prefix>_DEFAULT_PAGE, <prefix>_DEFAULT_SIZE, <prefix>_MAX_SIZE and
+// <prefix>_ALLOW_ALL, when set. Invalid values are reported as errors.
+func (c Config) FromEnv(prefix string) (Config, error) {
+	prefix = strings.ToUpper(prefix)
+	for _, f := range []struct {
+		name string
+		dst  *int32
+	}{
+		{"DEFAULT_PAGE", &c.DefaultPage},
+		{"DEFAULT_SIZE", &c.DefaultSize},
+		{"MAX_SIZE", &c.MaxSize},
+	} {
+		key := prefix + "_" + f.name
+		v, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return c, fmt.Errorf("pagination: invalid %s: %w", key, err)
+		}
+		*f.dst = int32(n)
+	}
+	key := prefix + "_ALLOW_ALL"
+	if v, ok := os.LookupEnv(key); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return c, fmt.Errorf("pagination: invalid %s: %w", key, err)
+		}
+		c.AllowAll = b
+	}
+	return c, nil
+}
@@ -0,0 +1,241 @@
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedCursorValue is returned when a cursor value has a type that
+// cannot be encoded into a page token.
+var ErrUnsupportedCursorValue = errors.New("unsupported cursor value")
+
+// SortKey describes a column used for keyset ordering.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// Cursor holds the sort-key values of the last row on a page.
+// Values of type string, bool, time.Time, []byte and the integer and float
+// types round-trip through page tokens with their types preserved, integers
+// being decoded as int64 or uint64 and floats as float64.
+type Cursor struct {
+	Values []any
+}
+
+// IsZero reports whether the cursor points at the first page.
+func (c Cursor) IsZero() bool {
+	return len(c.Values) == 0
+}
+
+// Predicate is a WHERE-clause fragment with positional bind arguments.
+type Predicate struct {
+	SQL  string
+	Args []any
+}
+
+// CursorPaginator defines the interface for keyset pagination.
+type CursorPaginator interface {
+	// ForCursor encodes the cursor into an opaque page token.
+	ForCursor(Cursor) (string, error)
+	// GetCursor decodes the cursor from the given page token.
+	GetCursor(string) (Cursor, error)
+	// Seek builds the predicate selecting the rows after the cursor.
+	Seek(Cursor) Predicate
+	// OrderBy renders the ORDER BY expression for the sort keys.
+	OrderBy() string
+}
+
+// ContextCursorPaginator is a CursorPaginator whose tokens can depend on the
+// request context, as with WithTokenAudience.
+type ContextCursorPaginator interface {
+	CursorPaginator
+	// ForCursorContext encodes the cursor into a page token for the context.
+	ForCursorContext(context.Context, Cursor) (string, error)
+	// GetCursorContext decodes the cursor from a page token for the context.
+	GetCursorContext(context.Context, string) (Cursor, error)
+}
+
+// NewCursorPaginator creates a CursorPaginator ordered by the given keys.
+// The token options are applied to the generated page tokens.
+func NewCursorPaginator(keys []SortKey, opts ...TokenOption) ContextCursorPaginator {
+	return &cursorPaginator{keys: keys, codec: NewTokenCodec[[]cursorValue](opts...)}
+}
+
+type cursorPaginator struct {
+	keys  []SortKey
+	codec ContextTokenCodec[[]cursorValue]
+}
+
+// ForCursor encodes the cursor into an opaque page token.
+func (p *cursorPaginator) ForCursor(c Cursor) (string, error) {
+	return p.ForCursorContext(context.Background(), c)
+}
+
+// GetCursor decodes the cursor from the given page token.
+func (p *cursorPaginator) GetCursor(token string) (Cursor, error) {
+	return p.GetCursorContext(context.Background(), token)
+}
+
+// ForCursorContext encodes the cursor into a page token for the context.
+func (p *cursorPaginator) ForCursorContext(ctx context.Context, c Cursor) (string, error) {
+	if c.IsZero() {
+		return "", nil
+	}
+	if len(c.Values) != len(p.keys) {
+		return "", ErrInvalidToken
+	}
+	values := make([]cursorValue, 0, len(c.Values))
+	for _, v := range c.Values {
+		cv, err := newCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, cv)
+	}
+	return p.codec.EncodeContext(ctx, values)
+}
+
+// GetCursorContext decodes the cursor from a page token for the context.
+func (p *cursorPaginator) GetCursorContext(ctx context.Context, token string) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+	values, err := p.codec.DecodeContext(ctx, token)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if len(values) != len(p.keys) {
+		return Cursor{}, ErrInvalidToken
+	}
+	c := Cursor{Values: make([]any, 0, len(values))}
+	for _, cv := range values {
+		v, err := cv.value()
+		if err != nil {
+			return Cursor{}, ErrInvalidToken
+		}
+		c.Values = append(c.Values, v)
+	}
+	return c, nil
+}
+
+// Seek builds the predicate selecting the rows after the cursor, e.g.
+// "(a > ? OR (a = ? AND b > ?))" for the keys a, b in ascending order.
+// It returns an empty predicate for the zero cursor.
+func (p *cursorPaginator) Seek(c Cursor) Predicate {
+	if c.IsZero() || len(c.Values) != len(p.keys) {
+		return Predicate{}
+	}
+	var (
+		terms []string
+		args  []any
+	)
+	for i, key := range p.keys {
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, p.keys[j].Column+" = ?")
+			args = append(args, c.Values[j])
+		}
+		op := " > ?"
+		if key.Desc {
+			op = " < ?"
+		}
+		conds = append(conds, key.Column+op)
+		args = append(args, c.Values[i])
+		if len(conds) == 1 {
+			terms = append(terms, conds[0])
+		} else {
+			terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+		}
+	}
+	return Predicate{
+		SQL:  "(" + strings.Join(terms, " OR ") + ")",
+		Args: args,
+	}
+}
+
+// OrderBy renders the ORDER BY expression for the sort keys.
+func (p *cursorPaginator) OrderBy() string {
+	cols := make([]string, 0, len(p.keys))
+	for _, key := range p.keys {
+		if key.Desc {
+			cols = append(cols, key.Column+" DESC")
+		} else {
+			cols = append(cols, key.Column+" ASC")
+		}
+	}
+	return strings.Join(cols, ", ")
+}
+
+// cursorValue is the typed wire form of a cursor value.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+func newCursorValue(v any) (cursorValue, error) {
+	switch v := v.(type) {
+	case nil:
+		return cursorValue{Type: "n"}, nil
+	case string:
+		return cursorValue{Type: "s", Value: v}, nil
+	case []byte:
+		return cursorValue{Type: "y", Value: base64.RawStdEncoding.EncodeToString(v)}, nil
+	case bool:
+		return cursorValue{Type: "b", Value: strconv.FormatBool(v)}, nil
+	case int:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int8:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int16:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int32:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int64:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(v, 10)}, nil
+	case uint:
+		return cursorValue{Type: "u", Value: strconv.FormatUint(uint64(v), 10)}, nil
+	case uint8:
+		return cursorValue{Type: "u", Value: strconv.FormatUint(uint64(v), 10)}, nil
+	case uint16:
+		return cursorValue{Type: "u", Value: strconv.FormatUint(uint64(v), 10)}, nil
+	case uint32:
+		return cursorValue{Type: "u", Value: strconv.FormatUint(uint64(v), 10)}, nil
+	case uint64:
+		return cursorValue{Type: "u", Value: strconv.FormatUint(v, 10)}, nil
+	case float32:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(float64(v), 'g', -1, 32)}, nil
+	case float64:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case time.Time:
+		return cursorValue{Type: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	}
+	return cursorValue{}, fmt.Errorf("%w: %T", ErrUnsupportedCursorValue, v)
+}
+
+func (cv cursorValue) value() (any, error) {
+	switch cv.Type {
+	case "n":
+		return nil, nil
+	case "s":
+		return cv.Value, nil
+	case "y":
+		return base64.RawStdEncoding.DecodeString(cv.Value)
+	case "b":
+		return strconv.ParseBool(cv.Value)
+	case "i":
+		return strconv.ParseInt(cv.Value, 10, 64)
+	case "u":
+		return strconv.ParseUint(cv.Value, 10, 64)
+	case "f":
+		return strconv.ParseFloat(cv.Value, 64)
+	case "t":
+		return time.Parse(time.RFC3339Nano, cv.Value)
+	}
+	return nil, ErrUnsupportedCursorValue
+}
@@ -0,0 +1,66 @@
+package pagination
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorSeekPredicate(t *testing.T) {
+	p := NewCursorPaginator([]SortKey{{Column: "created_at", Desc: true}, {Column: "id"}})
+
+	pred := p.Seek(Cursor{Values: []any{"2024-01-01", 7}})
+	want := "(created_at < ? OR (created_at = ? AND id > ?))"
+	if pred.SQL != want {
+		t.Fatalf("expected %q, got %q", want, pred.SQL)
+	}
+	if len(pred.Args) != 3 {
+		t.Fatalf("expected 3 args, got %d", len(pred.Args))
+	}
+	if got := p.OrderBy(); got != "created_at DESC, id ASC" {
+		t.Fatalf("unexpected order by %q", got)
+	}
+	if pred := p.Seek(Cursor{}); pred.SQL != "" {
+		t.Fatalf("expected empty predicate, got %q", pred.SQL)
+	}
+}
+
+func TestCursorTokenRoundTrip(t *testing.T) {
+	p := NewCursorPaginator([]SortKey{{Column: "name"}, {Column: "id"}, {Column: "created_at"}}, WithHMACKey([]byte("k")))
+
+	created := time.Date(2024, 5, 1, 12, 30, 0, 123, time.UTC)
+	token, err := p.ForCursor(Cursor{Values: []any{"bob", 3, created}})
+	if err != nil {
+		t.Fatalf("ForCursor returned unexpected error: %v", err)
+	}
+	c, err := p.GetCursor(token)
+	if err != nil {
+		t.Fatalf("GetCursor returned unexpected error: %v", err)
+	}
+	if c.Values[0] != "bob" || c.Values[1] != int64(3) || !c.Values[2].(time.Time).Equal(created) {
+		t.Fatalf("unexpected cursor values %v", c.Values)
+	}
+
+	other := NewCursorPaginator([]SortKey{{Column: "id"}}, WithHMACKey([]byte("k")))
+	if _, err := other.GetCursor(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestCursorTokenBytes(t *testing.T) {
+	p := NewCursorPaginator([]SortKey{{Column: "hash"}}, WithHMACKey([]byte("k")))
+
+	want := []byte{0xff, 0x00, 0x80}
+	token, err := p.ForCursor(Cursor{Values: []any{want}})
+	if err != nil {
+		t.Fatalf("ForCursor returned unexpected error: %v", err)
+	}
+	c, err := p.GetCursor(token)
+	if err != nil {
+		t.Fatalf("GetCursor returned unexpected error: %v", err)
+	}
+	if got, ok := c.Values[0].([]byte); !ok || !bytes.Equal(got, want) {
+		t.Fatalf("expected %x, got %x", want, c.Values[0])
+	}
+}
@@ -0,0 +1,76 @@
+// Package entpager applies pagination ranges and cursors to ent queries.
+package entpager
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// Range returns a selector modifier applying the offset and limit of the range,
+// for use with the Modify method of ent queries. A zero limit selects all
+// rows and adds no LIMIT clause.
+func Range(r pagination.PageRange) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		if r.Offset > 0 {
+			s.Offset(int(r.Offset))
+		}
+		if r.Limit > 0 {
+			s.Limit(int(r.Limit))
+		}
+	}
+}
+
+// Order returns the ent order options for the sort keys.
+func Order(keys ...pagination.SortKey) []func(*sql.Selector) {
+	opts := make([]func(*sql.Selector), 0, len(keys))
+	for _, key := range keys {
+		opts = append(opts, func(s *sql.Selector) {
+			if key.Desc {
+				s.OrderBy(sql.Desc(s.C(key.Column)))
+			} else {
+				s.OrderBy(sql.Asc(s.C(key.Column)))
+			}
+		})
+	}
+	return opts
+}
+
+// Seek returns a selector modifier selecting the rows after the cursor
+// for the sort keys. The zero cursor selects all rows.
+func Seek(keys []pagination.SortKey, c pagination.Cursor) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		if c.IsZero() || len(c.Values) != len(keys) {
+			return
+		}
+		ors := make([]*sql.Predicate, 0, len(keys))
+		for i, key := range keys {
+			ands := make([]*sql.Predicate, 0, i+1)
+			for j := 0; j < i; j++ {
+				ands = append(ands, sql.EQ(s.C(keys[j].Column), c.Values[j]))
+			}
+			if key.Desc {
+				ands = append(ands, sql.LT(s.C(key.Column), c.Values[i]))
+			} else {
+				ands = append(ands, sql.GT(s.C(key.Column), c.Values[i]))
+			}
+			ors = append(ors, sql.And(ands...))
+		}
+		s.Where(sql.Or(ors...))
+	}
+}
+
+// NextToken returns the page token following the first size items, built
+// from the sort-key values extract returns for the last entity of the page.
+// Callers should fetch up to size+1 items and return items[:size]: the token
+// is empty unless the extra lookahead item was fetched, so a last page of
+// exactly size items has no next page. The token is encoded for ctx, as with
+// pagination.WithTokenAudience.
+func NextToken[T any](ctx context.Context, p pagination.ContextCursorPaginator, items []T, size int, extract func(T) []any) (string, error) {
+	if size <= 0 || len(items) <= size {
+		return "", nil
+	}
+	return p.ForCursorContext(ctx, pagination.Cursor{Values: extract(items[size-1])})
+}
@@ -0,0 +1,75 @@
+package entpager
+
+import (
+	"context"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+func query(mods ...func(*sql.Selector)) string {
+	s := sql.Dialect(dialect.MySQL).Select("*").From(sql.Table("users"))
+	for _, mod := range mods {
+		mod(s)
+	}
+	q, _ := s.Query()
+	return q
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		r    pagination.PageRange
+		want string
+	}{
+		{pagination.PageRange{Offset: 20, Limit: 10}, "SELECT * FROM `users` LIMIT 10 OFFSET 20"},
+		{pagination.PageRange{}, "SELECT * FROM `users`"},
+	}
+	for _, tt := range tests {
+		if got := query(Range(tt.r)); got != tt.want {
+			t.Fatalf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestRangeUnpaginated(t *testing.T) {
+	p := pagination.NewPaginator(1, 20, pagination.WithAllowUnpaginated())
+	if got, want := query(Range(p.Resolve(1, -1))), "SELECT * FROM `users`"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSeekOrder(t *testing.T) {
+	keys := []pagination.SortKey{{Column: "name", Desc: true}, {Column: "id"}}
+	mods := append(Order(keys...), Seek(keys, pagination.Cursor{Values: []any{"bob", 3}}))
+	want := "SELECT * FROM `users` WHERE `users`.`name` < ? OR (`users`.`name` = ? AND `users`.`id` > ?) ORDER BY `users`.`name` DESC, `users`.`id` ASC"
+	if got := query(mods...); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, want := query(Seek(keys, pagination.Cursor{})), "SELECT * FROM `users`"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNextToken(t *testing.T) {
+	p := pagination.NewCursorPaginator([]pagination.SortKey{{Column: "id"}})
+	extract := func(id int) []any { return []any{id} }
+	ctx := context.Background()
+
+	if token, err := NextToken(ctx, p, []int{1, 2}, 3, extract); err != nil || token != "" {
+		t.Fatalf("expected no token for the last page, got %q, %v", token, err)
+	}
+	if token, err := NextToken(ctx, p, []int{1, 2, 3}, 3, extract); err != nil || token != "" {
+		t.Fatalf("expected no token for a full last page, got %q, %v", token, err)
+	}
+	token, err := NextToken(ctx, p, []int{1, 2, 3, 4}, 3, extract)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	c, err := p.GetCursor(token)
+	if err != nil || len(c.Values) != 1 || c.Values[0] != int64(3) {
+		t.Fatalf("expected cursor %v, got %v, %v", 3, c.Values, err)
+	}
+}
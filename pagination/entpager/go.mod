@@ -0,0 +1,12 @@
+module github.com/go-kratos/kit/pagination/entpager
+
+go 1.24.0
+
+replace github.com/go-kratos/kit => ../..
+
+require (
+	entgo.io/ent v0.14.6
+	github.com/go-kratos/kit v0.0.0
+)
+
+require github.com/google/uuid v1.3.0 // indirect
@@ -0,0 +1,86 @@
+// Package espager encodes Elasticsearch search_after sort values into page tokens.
+package espager
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// Pager builds search_after requests for a fixed sort order.
+type Pager struct {
+	keys  []pagination.SortKey
+	codec pagination.ContextTokenCodec[[]json.RawMessage]
+}
+
+// New creates a Pager sorting by the given keys, which should end with a
+// unique tiebreaker field. The token options are applied to the generated
+// page tokens.
+func New(keys []pagination.SortKey, opts ...pagination.TokenOption) *Pager {
+	return &Pager{
+		keys:  keys,
+		codec: pagination.NewTokenCodec[[]json.RawMessage](opts...),
+	}
+}
+
+// Sort returns the sort field of the search request body.
+func (p *Pager) Sort() []map[string]any {
+	sort := make([]map[string]any, 0, len(p.keys))
+	for _, key := range p.keys {
+		order := "asc"
+		if key.Desc {
+			order = "desc"
+		}
+		sort = append(sort, map[string]any{key.Column: map[string]any{"order": order}})
+	}
+	return sort
+}
+
+// Body returns the size, sort and search_after fields of the search request
+// body for the page token. search_after is omitted for the first page.
+func (p *Pager) Body(token string, size int) (map[string]any, error) {
+	return p.BodyContext(context.Background(), token, size)
+}
+
+// BodyContext is like Body, decoding the page token for the context.
+func (p *Pager) BodyContext(ctx context.Context, token string, size int) (map[string]any, error) {
+	values, err := p.codec.DecodeContext(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" && len(values) != len(p.keys) {
+		return nil, pagination.ErrInvalidToken
+	}
+	body := map[string]any{
+		"size": size,
+		"sort": p.Sort(),
+	}
+	if len(values) > 0 {
+		body["search_after"] = values
+	}
+	return body, nil
+}
+
+// NextPageToken returns the page token following the hit with the given
+// sort values, as returned in the "sort" field of the last hit.
+func (p *Pager) NextPageToken(sort []any) (string, error) {
+	return p.NextPageTokenContext(context.Background(), sort)
+}
+
+// NextPageTokenContext is like NextPageToken, encoding the page token for
+// the context.
+func (p *Pager) NextPageTokenContext(ctx context.Context, sort []any) (string, error) {
+	if len(sort) != len(p.keys) {
+		return "", pagination.ErrInvalidToken
+	}
+	values := make([]json.RawMessage, 0, len(sort))
+	for _, v := range sort {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, bs)
+	}
+	return p.codec.EncodeContext(ctx, values)
+}
@@ -0,0 +1,52 @@
+package espager
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+func TestPager(t *testing.T) {
+	p := New([]pagination.SortKey{{Column: "date", Desc: true}, {Column: "id"}})
+
+	body, err := p.Body("", 10)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, ok := body["search_after"]; ok {
+		t.Fatalf("expected no search_after on the first page, got %v", body)
+	}
+	bs, _ := json.Marshal(body["sort"])
+	if want := `[{"date":{"order":"desc"}},{"id":{"order":"asc"}}]`; string(bs) != want {
+		t.Fatalf("expected %s, got %s", want, bs)
+	}
+
+	token, err := p.NextPageToken([]any{1714560000000, "doc-7"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	body, err = p.Body(token, 10)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	bs, _ = json.Marshal(body["search_after"])
+	if want := `[1714560000000,"doc-7"]`; string(bs) != want {
+		t.Fatalf("expected %s, got %s", want, bs)
+	}
+}
+
+func TestPagerErrors(t *testing.T) {
+	p := New([]pagination.SortKey{{Column: "id"}})
+	if _, err := p.NextPageToken([]any{1, 2}); !errors.Is(err, pagination.ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", pagination.ErrInvalidToken, err)
+	}
+	token, _ := New([]pagination.SortKey{{Column: "a"}, {Column: "b"}}).NextPageToken([]any{1, 2})
+	if _, err := p.Body(token, 10); !errors.Is(err, pagination.ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", pagination.ErrInvalidToken, err)
+	}
+	if _, err := p.Body("bogus", 10); err == nil {
+		t.Fatal("expected error for a malformed token, got nil")
+	}
+}
@@ -0,0 +1,108 @@
+// Package filter implements the AIP-160 filtering language.
+package filter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Expr is a node of a parsed filter expression.
+type Expr interface {
+	// Accept calls the Visitor method matching the node.
+	Accept(Visitor) error
+}
+
+// Visitor visits the nodes of a filter expression.
+type Visitor interface {
+	VisitAnd(*AndExpr) error
+	VisitOr(*OrExpr) error
+	VisitNot(*NotExpr) error
+	VisitCompare(*CompareExpr) error
+	VisitCall(*CallExpr) error
+	VisitGlobal(*GlobalExpr) error
+}
+
+// AndExpr matches when all of its expressions match.
+type AndExpr struct {
+	Exprs []Expr
+}
+
+// OrExpr matches when any of its expressions matches.
+type OrExpr struct {
+	Exprs []Expr
+}
+
+// NotExpr matches when its expression does not match.
+type NotExpr struct {
+	Expr Expr
+}
+
+// CompareExpr compares a field with a value, such as `create_time > "2024"`.
+type CompareExpr struct {
+	Field string
+	Op    string
+	Value Value
+}
+
+// CallExpr is a function call, such as `regex(name, "^a")`.
+type CallExpr struct {
+	Name string
+	Args []Value
+}
+
+// GlobalExpr is a bare value matched against any field.
+type GlobalExpr struct {
+	Value Value
+}
+
+// Accept calls VisitAnd.
+func (e *AndExpr) Accept(v Visitor) error { return v.VisitAnd(e) }
+
+// Accept calls VisitOr.
+func (e *OrExpr) Accept(v Visitor) error { return v.VisitOr(e) }
+
+// Accept calls VisitNot.
+func (e *NotExpr) Accept(v Visitor) error { return v.VisitNot(e) }
+
+// Accept calls VisitCompare.
+func (e *CompareExpr) Accept(v Visitor) error { return v.VisitCompare(e) }
+
+// Accept calls VisitCall.
+func (e *CallExpr) Accept(v Visitor) error { return v.VisitCall(e) }
+
+// Accept calls VisitGlobal.
+func (e *GlobalExpr) Accept(v Visitor) error { return v.VisitGlobal(e) }
+
+// Value is a literal of a filter expression.
+type Value struct {
+	Text   string
+	Quoted bool
+}
+
+// IsNull reports whether the value is the unquoted null literal.
+func (v Value) IsNull() bool {
+	return !v.Quoted && v.Text == "null"
+}
+
+// Any converts the value into a bind argument. Unquoted literals are
+// converted to bool, int64 or float64 when possible.
+func (v Value) Any() any {
+	if v.Quoted {
+		return v.Text
+	}
+	switch v.Text {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if i, err := strconv.ParseInt(v.Text, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(v.Text, 64); err == nil && !strings.ContainsAny(v.Text, "xXpPnN") {
+		return f
+	}
+	return v.Text
+}
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToSQL(t *testing.T) {
+	fields := map[string]string{"name": "name", "age": "age", "create_time": "created_at"}
+
+	tests := []struct {
+		filter string
+		sql    string
+		args   []any
+	}{
+		{`age >= 18`, `age >= ?`, []any{int64(18)}},
+		{`name = "bob" OR name = "amy" age < 30`, `((name = ? OR name = ?) AND age < ?)`, []any{"bob", "amy", int64(30)}},
+		{`NOT (age > 1 AND create_time:*)`, `NOT ((age > ? AND created_at IS NOT NULL))`, []any{int64(1)}},
+		{`-name = "a*"`, `NOT (name LIKE ? ESCAPE '!')`, []any{"a%"}},
+		{`name = "*50%_off!\\*"`, `name LIKE ? ESCAPE '!'`, []any{`%50!%!_off!!\%`}},
+		{`name != null`, `name IS NOT NULL`, nil},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.filter)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned unexpected error: %v", tt.filter, err)
+		}
+		sql, args, err := ToSQL(e, fields)
+		if err != nil {
+			t.Fatalf("ToSQL(%q) returned unexpected error: %v", tt.filter, err)
+		}
+		if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+			t.Fatalf("ToSQL(%q) = %q %v, want %q %v", tt.filter, sql, args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestToSQLErrors(t *testing.T) {
+	if _, err := Parse(`(age > 1`); !errors.Is(err, ErrSyntax) {
+		t.Fatalf("expected %v, got %v", ErrSyntax, err)
+	}
+	deep := strings.Repeat("(", 100000) + "age > 1" + strings.Repeat(")", 100000)
+	if _, err := Parse(deep); !errors.Is(err, ErrSyntax) {
+		t.Fatalf("expected %v, got %v", ErrSyntax, err)
+	}
+	nested := strings.Repeat("(", maxDepth) + "age > 1" + strings.Repeat(")", maxDepth)
+	if _, err := Parse(nested); err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+	e, err := Parse(`password = "x"`)
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+	if _, _, err := ToSQL(e, map[string]string{"name": "name"}); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("expected %v, got %v", ErrUnknownField, err)
+	}
+}
@@ -0,0 +1,319 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSyntax is returned when a filter expression cannot be parsed.
+var ErrSyntax = errors.New("filter: syntax error")
+
+// maxDepth bounds the nesting of parentheses, so deeply nested input is
+// rejected instead of exhausting the stack.
+const maxDepth = 32
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokText
+	tokString
+	tokComparator
+	tokLParen
+	tokRParen
+	tokComma
+	tokMinus
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// Parse parses an AIP-160 filter expression. It returns a nil Expr for an
+// empty filter.
+func Parse(s string) (Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, nil
+	}
+	e, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, t.pos, fmt.Sprintf(format, args...))
+}
+
+// parseExpression parses sequences joined by AND.
+func (p *parser) parseExpression() (Expr, error) {
+	var exprs []Expr
+	for {
+		e, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if p.peek().kind != tokAnd {
+			break
+		}
+		p.next()
+	}
+	return and(exprs), nil
+}
+
+// parseSequence parses factors joined by whitespace, an implicit AND.
+func (p *parser) parseSequence() (Expr, error) {
+	var exprs []Expr
+	for {
+		e, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		switch p.peek().kind {
+		case tokText, tokString, tokLParen, tokMinus, tokNot:
+			continue
+		}
+		return and(exprs), nil
+	}
+}
+
+// parseFactor parses terms joined by OR, which binds tighter than AND.
+func (p *parser) parseFactor() (Expr, error) {
+	var exprs []Expr
+	for {
+		e, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if p.peek().kind != tokOr {
+			break
+		}
+		p.next()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return &OrExpr{Exprs: exprs}, nil
+}
+
+func (p *parser) parseTerm() (Expr, error) {
+	if k := p.peek().kind; k == tokNot || k == tokMinus {
+		p.next()
+		e, err := p.parseSimple()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Expr: e}, nil
+	}
+	return p.parseSimple()
+}
+
+func (p *parser) parseSimple() (Expr, error) {
+	if t := p.peek(); t.kind == tokLParen {
+		if p.depth == maxDepth {
+			return nil, p.errorf(t, "nesting exceeds %d levels", maxDepth)
+		}
+		p.next()
+		p.depth++
+		defer func() { p.depth-- }()
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.errorf(t, "expected \")\"")
+		}
+		return e, nil
+	}
+	return p.parseRestriction()
+}
+
+func (p *parser) parseRestriction() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokText:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t)
+		}
+	case tokString:
+	default:
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	if p.peek().kind != tokComparator {
+		return &GlobalExpr{Value: Value{Text: t.text, Quoted: t.kind == tokString}}, nil
+	}
+	if t.kind == tokString {
+		return nil, p.errorf(t, "expected field name")
+	}
+	op := p.next()
+	v, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &CompareExpr{Field: t.text, Op: op.text, Value: v}, nil
+}
+
+func (p *parser) parseCall(name token) (Expr, error) {
+	p.next()
+	call := &CallExpr{Name: name.text}
+	if p.peek().kind == tokRParen {
+		p.next()
+		return call, nil
+	}
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, v)
+		switch t := p.next(); t.kind {
+		case tokComma:
+		case tokRParen:
+			return call, nil
+		default:
+			return nil, p.errorf(t, "expected \",\" or \")\"")
+		}
+	}
+}
+
+func (p *parser) parseValue() (Value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokText:
+		return Value{Text: t.text}, nil
+	case tokString:
+		return Value{Text: t.text, Quoted: true}, nil
+	}
+	return Value{}, p.errorf(t, "expected value")
+}
+
+func and(exprs []Expr) Expr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return &AndExpr{Exprs: exprs}
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case c == '<' || c == '>' || c == '!' || c == '=' || c == ':':
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' && c != '=' && c != ':' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("%w at position %d: unexpected \"!\"", ErrSyntax, i)
+			}
+			tokens = append(tokens, token{kind: tokComparator, text: op, pos: i})
+			i += len(op)
+		case c == '"' || c == '\'':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w at position %d: %v", ErrSyntax, i, err)
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, pos: i})
+			i += n
+		case c == '-' && (i+1 >= len(s) || !isDigit(s[i+1])):
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: i})
+			i++
+		default:
+			j := i + 1
+			for j < len(s) && isTextChar(s[j]) {
+				j++
+			}
+			t := token{kind: tokText, text: s[i:j], pos: i}
+			switch t.text {
+			case "AND":
+				t.kind = tokAnd
+			case "OR":
+				t.kind = tokOr
+			case "NOT":
+				t.kind = tokNot
+			}
+			tokens = append(tokens, t)
+			i = j
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+// lexString reads a quoted string, returning its unescaped text and length.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 == len(s) {
+				break
+			}
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isTextChar(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '(', ')', ',', '<', '>', '!', '=', ':', '"', '\'':
+		return false
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnknownField is returned when a filter references a field outside of the allowlist.
+	ErrUnknownField = errors.New("filter: unknown field")
+	// ErrUnsupported is returned when a filter uses a construct the translator cannot express.
+	ErrUnsupported = errors.New("filter: unsupported expression")
+)
+
+// Function translates a function call into an SQL condition with bind arguments.
+type Function func(args []Value) (string, []any, error)
+
+// SQLOption defines options for ToSQL.
+type SQLOption func(*sqlVisitor)
+
+// WithFunction registers the translation of the named function.
+func WithFunction(name string, fn Function) SQLOption {
+	return func(v *sqlVisitor) {
+		v.funcs[name] = fn
+	}
+}
+
+// ToSQL translates the expression into an SQL WHERE condition with "?"
+// bind parameters. fields maps the filterable fields to their columns;
+// any other field is rejected with ErrUnknownField.
+func ToSQL(e Expr, fields map[string]string, opts ...SQLOption) (string, []any, error) {
+	if e == nil {
+		return "", nil, nil
+	}
+	v := &sqlVisitor{fields: fields, funcs: make(map[string]Function)}
+	for _, o := range opts {
+		o(v)
+	}
+	if err := e.Accept(v); err != nil {
+		return "", nil, err
+	}
+	return v.b.String(), v.args, nil
+}
+
+type sqlVisitor struct {
+	fields map[string]string
+	funcs  map[string]Function
+	b      strings.Builder
+	args   []any
+}
+
+func (v *sqlVisitor) join(exprs []Expr, sep string) error {
+	v.b.WriteByte('(')
+	for i, e := range exprs {
+		if i > 0 {
+			v.b.WriteString(sep)
+		}
+		if err := e.Accept(v); err != nil {
+			return err
+		}
+	}
+	v.b.WriteByte(')')
+	return nil
+}
+
+// VisitAnd writes the conjunction of the expressions.
+func (v *sqlVisitor) VisitAnd(e *AndExpr) error {
+	return v.join(e.Exprs, " AND ")
+}
+
+// VisitOr writes the disjunction of the expressions.
+func (v *sqlVisitor) VisitOr(e *OrExpr) error {
+	return v.join(e.Exprs, " OR ")
+}
+
+// VisitNot writes the negation of the expression.
+func (v *sqlVisitor) VisitNot(e *NotExpr) error {
+	v.b.WriteString("NOT ")
+	return v.join([]Expr{e.Expr}, "")
+}
+
+// VisitCompare writes the comparison of a column with a bind argument.
+func (v *sqlVisitor) VisitCompare(e *CompareExpr) error {
+	col, ok := v.fields[e.Field]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownField, e.Field)
+	}
+	switch e.Op {
+	case ":":
+		if !e.Value.Quoted && e.Value.Text == "*" {
+			v.b.WriteString(col + " IS NOT NULL")
+			return nil
+		}
+		v.b.WriteString(col + " = ?")
+	case "=", "!=":
+		if e.Value.IsNull() {
+			if e.Op == "=" {
+				v.b.WriteString(col + " IS NULL")
+			} else {
+				v.b.WriteString(col + " IS NOT NULL")
+			}
+			return nil
+		}
+		if pattern, ok := wildcard(e.Value); ok {
+			if e.Op == "=" {
+				v.b.WriteString(col + " LIKE ? ESCAPE '!'")
+			} else {
+				v.b.WriteString(col + " NOT LIKE ? ESCAPE '!'")
+			}
+			v.args = append(v.args, pattern)
+			return nil
+		}
+		if e.Op == "=" {
+			v.b.WriteString(col + " = ?")
+		} else {
+			v.b.WriteString(col + " <> ?")
+		}
+	case "<", "<=", ">", ">=":
+		v.b.WriteString(col + " " + e.Op + " ?")
+	default:
+		return fmt.Errorf("%w: operator %q", ErrUnsupported, e.Op)
+	}
+	v.args = append(v.args, e.Value.Any())
+	return nil
+}
+
+// VisitCall writes the translation of a registered function.
+func (v *sqlVisitor) VisitCall(e *CallExpr) error {
+	fn, ok := v.funcs[e.Name]
+	if !ok {
+		return fmt.Errorf("%w: function %q", ErrUnsupported, e.Name)
+	}
+	cond, args, err := fn(e.Args)
+	if err != nil {
+		return err
+	}
+	v.b.WriteString(cond)
+	v.args = append(v.args, args...)
+	return nil
+}
+
+// VisitGlobal rejects bare values, which have no column to match.
+func (v *sqlVisitor) VisitGlobal(e *GlobalExpr) error {
+	return fmt.Errorf("%w: global restriction %q", ErrUnsupported, e.Value.Text)
+}
+
+// wildcard converts a quoted string with a leading or trailing "*" into a LIKE
+// pattern. "%", "_" and the escape character are escaped with "!", which,
+// unlike a backslash, needs no escaping in the SQL literal of any dialect.
+func wildcard(val Value) (string, bool) {
+	if !val.Quoted || len(val.Text) < 2 {
+		return "", false
+	}
+	if !strings.HasPrefix(val.Text, "*") && !strings.HasSuffix(val.Text, "*") {
+		return "", false
+	}
+	pattern := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(val.Text)
+	if strings.HasPrefix(pattern, "*") {
+		pattern = "%" + pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "*") {
+		pattern = pattern[:len(pattern)-1] + "%"
+	}
+	return pattern, true
+}
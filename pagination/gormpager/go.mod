@@ -0,0 +1,16 @@
+module github.com/go-kratos/kit/pagination/gormpager
+
+go 1.24.0
+
+replace github.com/go-kratos/kit => ../..
+
+require (
+	github.com/go-kratos/kit v0.0.0
+	gorm.io/gorm v1.31.2
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
@@ -0,0 +1,34 @@
+// Package gormpager applies pagination ranges and cursors to GORM queries.
+package gormpager
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// Scope returns a GORM scope applying the offset and limit of the range.
+// A zero limit selects all rows and adds no LIMIT clause.
+func Scope(r pagination.PageRange) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Offset(int(r.Offset))
+		if r.Limit > 0 {
+			db = db.Limit(int(r.Limit))
+		}
+		return db
+	}
+}
+
+// CursorScope returns a GORM scope applying the seek predicate and ordering
+// of the paginator for the cursor, limited to size rows.
+func CursorScope(p pagination.CursorPaginator, c pagination.Cursor, size int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if pred := p.Seek(c); pred.SQL != "" {
+			db = db.Where(pred.SQL, pred.Args...)
+		}
+		if size > 0 {
+			db = db.Limit(size)
+		}
+		return db.Order(p.OrderBy())
+	}
+}
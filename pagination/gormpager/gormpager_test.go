@@ -0,0 +1,65 @@
+package gormpager
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func dryRun(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	return db
+}
+
+func TestScope(t *testing.T) {
+	cases := []struct {
+		r    pagination.PageRange
+		want string
+	}{
+		{pagination.PageRange{Offset: 20, Limit: 10}, "SELECT * FROM `users` LIMIT ? OFFSET ?"},
+		{pagination.PageRange{}, "SELECT * FROM `users`"},
+	}
+	for _, tt := range cases {
+		stmt := dryRun(t).Scopes(Scope(tt.r)).Find(&[]user{}).Statement
+		if got := strings.TrimSpace(stmt.SQL.String()); got != tt.want {
+			t.Fatalf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestScopeUnpaginated(t *testing.T) {
+	p := pagination.NewPaginator(1, 20, pagination.WithAllowUnpaginated())
+	stmt := dryRun(t).Scopes(Scope(p.Resolve(1, -1))).Find(&[]user{}).Statement
+	if got, want := strings.TrimSpace(stmt.SQL.String()), "SELECT * FROM `users`"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCursorScope(t *testing.T) {
+	p := pagination.NewCursorPaginator([]pagination.SortKey{{Column: "name", Desc: true}, {Column: "id"}})
+
+	stmt := dryRun(t).Scopes(CursorScope(p, pagination.Cursor{}, 10)).Find(&[]user{}).Statement
+	if got, want := strings.TrimSpace(stmt.SQL.String()), "SELECT * FROM `users` ORDER BY name DESC, id ASC LIMIT ?"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	stmt = dryRun(t).Scopes(CursorScope(p, pagination.Cursor{Values: []any{"bob", 3}}, 10)).Find(&[]user{}).Statement
+	want := "SELECT * FROM `users` WHERE (name < ? OR (name = ? AND id > ?)) ORDER BY name DESC, id ASC LIMIT ?"
+	if got := strings.TrimSpace(stmt.SQL.String()); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if len(stmt.Vars) != 4 {
+		t.Fatalf("expected %v vars, got %v", 4, stmt.Vars)
+	}
+}
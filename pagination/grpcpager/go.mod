@@ -0,0 +1,18 @@
+module github.com/go-kratos/kit/pagination/grpcpager
+
+go 1.24.0
+
+replace github.com/go-kratos/kit => ../..
+
+require (
+	github.com/go-kratos/kit v0.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250603155806-513f23925822
+	google.golang.org/grpc v1.73.0
+)
+
+require (
+	golang.org/x/net v0.50.0 // indirect
+	golang.org/x/sys v0.41.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
+	google.golang.org/protobuf v1.36.12 // indirect
+)
@@ -0,0 +1,77 @@
+// Package grpcpager validates the pagination fields of gRPC requests.
+package grpcpager
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// UnaryServerInterceptor returns a unary interceptor validating requests that
+// implement pagination.PageRequest or pagination.ListRequest with
+// pagination.ParseStrict before the handler runs. Invalid requests, including
+// empty pages requested under ZeroSizeEmpty, are rejected with InvalidArgument
+// carrying BadRequest details, as the HTTP Middleware does. The resolved
+// parameters of valid ones are stored in the context for pagination.FromContext.
+func UnaryServerInterceptor(p pagination.Paginator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var pr pagination.PageRequest
+		switch r := req.(type) {
+		case pagination.PageRequest:
+			pr = r
+		case pagination.ListRequest:
+			pr = listRequest{r}
+		default:
+			return handler(ctx, req)
+		}
+		rng, err := pagination.ParseStrict(p, pr)
+		if err != nil {
+			return nil, invalidArgument(err)
+		}
+		params := pagination.Params{Range: rng}
+		if tr, ok := req.(pagination.TokenRequest); ok {
+			params.PageToken = tr.GetPageToken()
+		}
+		return handler(pagination.NewContext(ctx, params), req)
+	}
+}
+
+// listRequest adapts an AIP-158 request without page numbers.
+type listRequest struct {
+	pagination.ListRequest
+}
+
+func (listRequest) GetPageNum() int32 { return 0 }
+
+// GetSkip returns the skip of requests implementing pagination.SkipRequest.
+func (r listRequest) GetSkip() int32 {
+	if sr, ok := r.ListRequest.(pagination.SkipRequest); ok {
+		return sr.GetSkip()
+	}
+	return 0
+}
+
+func invalidArgument(err error) error {
+	var verr *pagination.ValidationError
+	if !errors.As(err, &verr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	st, derr := status.New(codes.InvalidArgument, verr.Error()).WithDetails(br)
+	if derr != nil {
+		return status.Error(codes.InvalidArgument, verr.Error())
+	}
+	return st.Err()
+}
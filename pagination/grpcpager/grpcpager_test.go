@@ -0,0 +1,62 @@
+package grpcpager
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+type testRequest struct {
+	size, skip int32
+	token      string
+}
+
+func (r *testRequest) GetPageSize() int32   { return r.size }
+func (r *testRequest) GetSkip() int32       { return r.skip }
+func (r *testRequest) GetPageToken() string { return r.token }
+
+func intercept(p pagination.Paginator, req any) (pagination.Params, error) {
+	var params pagination.Params
+	_, err := UnaryServerInterceptor(p)(context.Background(), req, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		params, _ = pagination.FromContext(ctx)
+		return nil, nil
+	})
+	return params, err
+}
+
+func TestInterceptorSkip(t *testing.T) {
+	tokens := pagination.NewTokenGenerator()
+	p := pagination.NewPaginator(1, 20, pagination.WithPageTokens(tokens))
+
+	params, err := intercept(p, &testRequest{size: 10, skip: 5, token: tokens.ForIndex(40)})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if want := (pagination.PageRange{Offset: 45, Limit: 10}); params.Range != want {
+		t.Fatalf("expected %+v, got %+v", want, params.Range)
+	}
+	if _, err := intercept(p, &testRequest{size: 10, skip: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected %v, got %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestInterceptorEmptyPage(t *testing.T) {
+	p := pagination.NewPaginator(1, 20, pagination.WithZeroSize(pagination.ZeroSizeEmpty))
+
+	called := false
+	_, err := UnaryServerInterceptor(p)(context.Background(), &testRequest{}, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+	if status.Code(err) != codes.InvalidArgument || called {
+		t.Fatalf("expected %v without calling the handler, got %v", codes.InvalidArgument, err)
+	}
+	if params, err := intercept(p, &testRequest{size: 10}); err != nil || params.Range.Limit != 10 {
+		t.Fatalf("expected limit %v, got %+v, %v", 10, params.Range, err)
+	}
+}
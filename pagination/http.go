@@ -0,0 +1,146 @@
+package pagination
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// Params holds the pagination parameters resolved from a request.
+type Params struct {
+	Range     PageRange
+	PageToken string
+	OrderBy   []OrderBy
+}
+
+type paramsKey struct{}
+
+// NewContext returns a new Context that carries the pagination parameters.
+func NewContext(ctx context.Context, params Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// FromContext returns the pagination parameters stored in ctx, if any.
+func FromContext(ctx context.Context) (Params, bool) {
+	params, ok := ctx.Value(paramsKey{}).(Params)
+	return params, ok
+}
+
+// Middleware returns a net/http middleware, which is also usable as a kratos
+// HTTP transport filter. It reads the page, page_size, page_token and order_by
+// query parameters, validates them with ParseStrict and stores the resolved
+// Params in the request context. order_by is restricted to allowedOrderBy
+// when it is not empty. Invalid requests are answered with 400 Bad Request.
+func Middleware(p Paginator, allowedOrderBy ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			req := &queryRequest{token: q.Get("page_token")}
+			var err error
+			if req.page, err = queryInt(q.Get("page")); err != nil {
+				http.Error(w, "invalid page", http.StatusBadRequest)
+				return
+			}
+			if req.size, err = queryInt(q.Get("page_size")); err != nil {
+				http.Error(w, "invalid page_size", http.StatusBadRequest)
+				return
+			}
+			rng, err := ParseStrict(p, req)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			orders, err := ParseOrderBy(q.Get("order_by"), allowedOrderBy...)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ctx := NewContext(r.Context(), Params{Range: rng, PageToken: req.token, OrderBy: orders})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type queryRequest struct {
+	page  int32
+	size  int32
+	token string
+}
+
+func (r *queryRequest) GetPageNum() int32    { return r.page }
+func (r *queryRequest) GetPageSize() int32   { return r.size }
+func (r *queryRequest) GetPageToken() string { return r.token }
+
+func queryInt(s string) (int32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	return int32(n), err
+}
+
+// Pagination metadata headers for REST endpoints returning bare arrays.
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderPage       = "X-Page"
+	HeaderNextToken  = "X-Next-Token"
+)
+
+// HeaderMetadata is the pagination metadata carried in response headers.
+// A negative TotalCount and a zero Page are omitted.
+type HeaderMetadata struct {
+	TotalCount    int64
+	Page          int32
+	NextPageToken string
+}
+
+// PageMetadata returns the header metadata of the page with the given number.
+// The total is included only when it has been resolved with Page.Total or set,
+// a resolved or set total of 0 included; a TotalSize of -1 means unknown.
+func PageMetadata[T any](p *Page[T], page int32) HeaderMetadata {
+	m := HeaderMetadata{TotalCount: -1, Page: page, NextPageToken: p.NextPageToken}
+	if p.total != nil && !p.total.resolved {
+		return m
+	}
+	if p.total != nil {
+		m.TotalCount, _ = p.Total()
+	} else if p.TotalSize >= 0 {
+		m.TotalCount = p.TotalSize
+	}
+	return m
+}
+
+// WriteHeaders writes the pagination metadata headers onto w.
+func WriteHeaders(w http.ResponseWriter, m HeaderMetadata) {
+	h := w.Header()
+	if m.TotalCount >= 0 {
+		h.Set(HeaderTotalCount, strconv.FormatInt(m.TotalCount, 10))
+	}
+	if m.Page > 0 {
+		h.Set(HeaderPage, strconv.FormatInt(int64(m.Page), 10))
+	}
+	if m.NextPageToken != "" {
+		h.Set(HeaderNextToken, m.NextPageToken)
+	}
+}
+
+// ParseHeaders reads the pagination metadata headers of a response.
+// A missing total is returned as -1.
+func ParseHeaders(h http.Header) (HeaderMetadata, error) {
+	m := HeaderMetadata{TotalCount: -1, NextPageToken: h.Get(HeaderNextToken)}
+	if s := h.Get(HeaderTotalCount); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return HeaderMetadata{}, err
+		}
+		m.TotalCount = n
+	}
+	if s := h.Get(HeaderPage); s != "" {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return HeaderMetadata{}, err
+		}
+		m.Page = int32(n)
+	}
+	return m, nil
+}
@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	tokens := NewTokenGenerator()
+	p := NewPaginator(1, 20, WithMaxSize(50), WithPageTokens(tokens))
+	var got Params
+	h := Middleware(p, "name")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?page=3&page_size=10&order_by=name%20desc", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %v, got %v", http.StatusOK, w.Code)
+	}
+	if want := (PageRange{Offset: 20, Limit: 10}); got.Range != want {
+		t.Fatalf("expected %+v, got %+v", want, got.Range)
+	}
+	if len(got.OrderBy) != 1 || got.OrderBy[0] != (OrderBy{Field: "name", Desc: true}) {
+		t.Fatalf("unexpected order by %+v", got.OrderBy)
+	}
+
+	token := tokens.ForIndex(40)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?page_size=5&page_token="+token, nil))
+	if got.Range.Offset != 40 || got.PageToken != token {
+		t.Fatalf("expected the token position %v, got %+v", 40, got)
+	}
+
+	for _, query := range []string{"page=x", "page_size=51", "page_size=-1", "order_by=password", "page_token=bogus"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected %v for %q, got %v", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteHeaders(w, HeaderMetadata{TotalCount: 42, Page: 2, NextPageToken: "next"})
+	m, err := ParseHeaders(w.Header())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if want := (HeaderMetadata{TotalCount: 42, Page: 2, NextPageToken: "next"}); m != want {
+		t.Fatalf("expected %+v, got %+v", want, m)
+	}
+	m, err = ParseHeaders(http.Header{})
+	if err != nil || m.TotalCount != -1 {
+		t.Fatalf("expected a missing total, got %+v, %v", m, err)
+	}
+	if _, err := ParseHeaders(http.Header{HeaderTotalCount: {"x"}}); err == nil {
+		t.Fatal("expected error for a malformed total, got nil")
+	}
+}
+
+func TestPageMetadataTotal(t *testing.T) {
+	gen := NewTokenGenerator()
+	p := NewPage([]int{1, 2}, gen, 0, 10, WithTotalProvider(func() (int64, error) { return 2, nil }))
+	if m := PageMetadata(p, 1); m.TotalCount != -1 {
+		t.Fatalf("expected the unresolved total to be omitted, got %v", m.TotalCount)
+	}
+	w := httptest.NewRecorder()
+	WriteHeaders(w, PageMetadata(p, 1))
+	if got := w.Header().Get(HeaderTotalCount); got != "" {
+		t.Fatalf("expected no %s header, got %q", HeaderTotalCount, got)
+	}
+	if _, err := p.Total(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if m := PageMetadata(p, 1); m.TotalCount != 2 {
+		t.Fatalf("expected %v, got %v", 2, m.TotalCount)
+	}
+
+	set := &Page[int]{TotalSize: 5}
+	if m := PageMetadata(set, 1); m.TotalCount != 5 {
+		t.Fatalf("expected %v, got %v", 5, m.TotalCount)
+	}
+}
+
+func TestPageMetadataZeroTotal(t *testing.T) {
+	gen := NewTokenGenerator()
+	if m := PageMetadata(NewPage([]int{}, gen, 0, 10), 1); m.TotalCount != -1 {
+		t.Fatalf("expected an unknown total to be omitted, got %v", m.TotalCount)
+	}
+	p := NewPage([]int{}, gen, 0, 10)
+	p.TotalSize = 0
+	if m := PageMetadata(p, 1); m.TotalCount != 0 {
+		t.Fatalf("expected a set total of %v, got %v", 0, m.TotalCount)
+	}
+	p = NewPage([]int{}, gen, 0, 10, WithTotalProvider(func() (int64, error) { return 0, nil }))
+	p.Total()
+	if m := PageMetadata(p, 1); m.TotalCount != 0 {
+		t.Fatalf("expected a resolved total of %v, got %v", 0, m.TotalCount)
+	}
+	w := httptest.NewRecorder()
+	WriteHeaders(w, PageMetadata(p, 1))
+	if got := w.Header().Get(HeaderTotalCount); got != "0" {
+		t.Fatalf("expected %s %q, got %q", HeaderTotalCount, "0", got)
+	}
+}
@@ -0,0 +1,75 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// ErrRepeatedToken is returned when a page fetch returns the token it was
+// called with, which would otherwise loop forever.
+var ErrRepeatedToken = errors.New("repeated page token")
+
+// Iterate returns an iterator over all items of the pages returned by fetch,
+// following next page tokens until an empty one is returned. Errors of fetch
+// and of ctx are yielded once and end the iteration.
+func Iterate[T any](ctx context.Context, fetch func(token string) (items []T, next string, err error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var (
+			zero  T
+			token string
+		)
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			items, next, err := fetch(token)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			if next == token {
+				yield(zero, ErrRepeatedToken)
+				return
+			}
+			token = next
+		}
+	}
+}
+
+// Stream returns an iterator over the pages produced by fetch, starting from
+// the zero cursor and following the returned cursors until a zero cursor is
+// returned. Pages are fetched lazily as the consumer pulls them, so a slow
+// consumer, such as a server-streaming RPC, throttles the datastore reads.
+func Stream[T any](ctx context.Context, fetch func(ctx context.Context, c Cursor) (items []T, next Cursor, err error)) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		var c Cursor
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			items, next, err := fetch(ctx, c)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(items) > 0 && !yield(items, nil) {
+				return
+			}
+			if next.IsZero() {
+				return
+			}
+			c = next
+		}
+	}
+}
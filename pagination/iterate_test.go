@@ -0,0 +1,152 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestIterate(t *testing.T) {
+	pages := map[string]struct {
+		items []int
+		next  string
+	}{
+		"":  {[]int{1, 2}, "b"},
+		"b": {[]int{3}, "c"},
+		"c": {nil, ""},
+	}
+	var got []int
+	for item, err := range Iterate(context.Background(), func(token string) ([]int, string, error) {
+		return pages[token].items, pages[token].next, nil
+	}) {
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		got = append(got, item)
+	}
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected %v, got %v", []int{1, 2, 3}, got)
+	}
+}
+
+func TestIterateErrors(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	tests := []struct {
+		fetch func(token string) ([]int, string, error)
+		want  error
+	}{
+		{func(token string) ([]int, string, error) { return []int{1}, "a", nil }, ErrRepeatedToken},
+		{func(token string) ([]int, string, error) {
+			if token == "" {
+				return []int{1}, "a", nil
+			}
+			return nil, "", errFetch
+		}, errFetch},
+	}
+	for _, tt := range tests {
+		var errs []error
+		for _, err := range Iterate(context.Background(), tt.fetch) {
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], tt.want) {
+			t.Fatalf("expected a single %v, got %v", tt.want, errs)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var fetches int
+	for _, err := range Iterate(ctx, func(token string) ([]int, string, error) {
+		fetches++
+		return []int{1}, token + "x", nil
+	}) {
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected %v, got %v", context.Canceled, err)
+			}
+			break
+		}
+		cancel()
+	}
+	if fetches != 1 {
+		t.Fatalf("expected the cancellation to stop fetching, got %v fetches", fetches)
+	}
+}
+
+func TestIterateBreak(t *testing.T) {
+	var fetches int
+	for range Iterate(context.Background(), func(token string) ([]int, string, error) {
+		fetches++
+		return []int{1, 2}, token + "x", nil
+	}) {
+		break
+	}
+	if fetches != 1 {
+		t.Fatalf("expected %v fetch, got %v", 1, fetches)
+	}
+}
+
+func TestStream(t *testing.T) {
+	var pages [][]int
+	for items, err := range Stream(context.Background(), func(ctx context.Context, c Cursor) ([]int, Cursor, error) {
+		switch {
+		case c.IsZero():
+			return []int{1, 2}, Cursor{Values: []any{2}}, nil
+		case c.Values[0] == 2:
+			return nil, Cursor{Values: []any{3}}, nil
+		default:
+			return []int{3}, Cursor{}, nil
+		}
+	}) {
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		pages = append(pages, items)
+	}
+	if len(pages) != 2 || !slices.Equal(pages[0], []int{1, 2}) || !slices.Equal(pages[1], []int{3}) {
+		t.Fatalf("expected the empty page to be skipped, got %v", pages)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	var errs []error
+	for _, err := range Stream(context.Background(), func(ctx context.Context, c Cursor) ([]int, Cursor, error) {
+		if c.IsZero() {
+			return []int{1}, Cursor{Values: []any{1}}, nil
+		}
+		return nil, Cursor{}, errFetch
+	}) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], errFetch) {
+		t.Fatalf("expected a single %v, got %v", errFetch, errs)
+	}
+
+	var fetches int
+	for range Stream(context.Background(), func(ctx context.Context, c Cursor) ([]int, Cursor, error) {
+		fetches++
+		return []int{1}, Cursor{Values: []any{fetches}}, nil
+	}) {
+		break
+	}
+	if fetches != 1 {
+		t.Fatalf("expected pages to be fetched lazily, got %v fetches", fetches)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range Stream(ctx, func(ctx context.Context, c Cursor) ([]int, Cursor, error) {
+		t.Fatal("expected no fetch after cancellation")
+		return nil, Cursor{}, nil
+	}) {
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	}
+}
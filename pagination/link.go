@@ -0,0 +1,76 @@
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LinkHeader builds an RFC 5988 Link header value with the "first", "prev",
+// "next" and "last" links of the page r within total results. The links set
+// the page and page_size query parameters of baseURL.
+func LinkHeader(baseURL string, r PageRange, total int64) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	if r.Limit <= 0 {
+		return "", nil
+	}
+	size := int64(r.Limit)
+	page := int64(r.Offset)/size + 1
+	last := max(Totals(total, r.Limit).TotalPages, 1)
+	link := func(p int64) string {
+		q := u.Query()
+		q.Set("page", strconv.FormatInt(p, 10))
+		q.Set("page_size", strconv.FormatInt(size, 10))
+		return withQuery(u, q)
+	}
+	var links []string
+	links = append(links, formatLink(link(1), "first"))
+	if page > 1 {
+		links = append(links, formatLink(link(min(page-1, last)), "prev"))
+	}
+	if page < last {
+		links = append(links, formatLink(link(page+1), "next"))
+	}
+	links = append(links, formatLink(link(last), "last"))
+	return strings.Join(links, ", "), nil
+}
+
+// TokenLinkHeader builds an RFC 5988 Link header value with the "first",
+// "prev" and "next" links of a token-paginated page. The links set the
+// page_token query parameter of baseURL.
+func TokenLinkHeader(baseURL string, info PageInfo) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	link := func(token string) string {
+		q := u.Query()
+		if token == "" {
+			q.Del("page_token")
+		} else {
+			q.Set("page_token", token)
+		}
+		return withQuery(u, q)
+	}
+	links := []string{formatLink(link(""), "first")}
+	if info.HasPreviousPage {
+		links = append(links, formatLink(link(info.PreviousPageToken), "prev"))
+	}
+	if info.HasNextPage {
+		links = append(links, formatLink(link(info.NextPageToken), "next"))
+	}
+	return strings.Join(links, ", "), nil
+}
+
+func withQuery(u *url.URL, q url.Values) string {
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+func formatLink(target, rel string) string {
+	return "<" + target + `>; rel="` + rel + `"`
+}
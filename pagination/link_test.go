@@ -0,0 +1,46 @@
+package pagination
+
+import "testing"
+
+func TestLinkHeader(t *testing.T) {
+	tests := []struct {
+		r     PageRange
+		total int64
+		want  string
+	}{
+		{PageRange{Offset: 10, Limit: 10}, 35,
+			`</users?page=1&page_size=10&q=a>; rel="first", </users?page=1&page_size=10&q=a>; rel="prev", </users?page=3&page_size=10&q=a>; rel="next", </users?page=4&page_size=10&q=a>; rel="last"`},
+		{PageRange{Offset: 0, Limit: 10}, 5,
+			`</users?page=1&page_size=10&q=a>; rel="first", </users?page=1&page_size=10&q=a>; rel="last"`},
+		{PageRange{Offset: 90, Limit: 10}, 20,
+			`</users?page=1&page_size=10&q=a>; rel="first", </users?page=2&page_size=10&q=a>; rel="prev", </users?page=2&page_size=10&q=a>; rel="last"`},
+		{PageRange{}, 20, ""},
+	}
+	for _, tt := range tests {
+		got, err := LinkHeader("/users?q=a", tt.r, tt.total)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected %s, got %s", tt.want, got)
+		}
+	}
+	if _, err := LinkHeader("://bad", PageRange{Limit: 10}, 1); err == nil {
+		t.Fatal("expected an error for an invalid URL, got nil")
+	}
+}
+
+func TestTokenLinkHeader(t *testing.T) {
+	info := PageInfo{HasNextPage: true, HasPreviousPage: true, NextPageToken: "n+1", PreviousPageToken: "p"}
+	got, err := TokenLinkHeader("/users?page_token=cur&q=a", info)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	want := `</users?q=a>; rel="first", </users?page_token=p&q=a>; rel="prev", </users?page_token=n%2B1&q=a>; rel="next"`
+	if got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got, _ := TokenLinkHeader("/users", PageInfo{}); got != `</users>; rel="first"` {
+		t.Fatalf("expected only the first link, got %s", got)
+	}
+}
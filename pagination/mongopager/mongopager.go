@@ -0,0 +1,79 @@
+// Package mongopager applies pagination ranges and cursors to MongoDB queries.
+package mongopager
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// FindOptions converts the range and sort keys into find options.
+func FindOptions(r pagination.PageRange, keys ...pagination.SortKey) *options.FindOptions {
+	opts := options.Find().SetSkip(int64(r.Offset)).SetLimit(int64(r.Limit))
+	if len(keys) > 0 {
+		opts.SetSort(Sort(keys...))
+	}
+	return opts
+}
+
+// Sort converts the sort keys into a sort document.
+func Sort(keys ...pagination.SortKey) bson.D {
+	sort := make(bson.D, 0, len(keys))
+	for _, key := range keys {
+		dir := 1
+		if key.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: key.Column, Value: dir})
+	}
+	return sort
+}
+
+// IDPager pages through a collection in _id order, which avoids the cost
+// of skipping documents on large collections.
+type IDPager struct {
+	codec pagination.ContextTokenCodec[primitive.ObjectID]
+}
+
+// NewIDPager creates an IDPager. The token options are applied to the
+// generated page tokens.
+func NewIDPager(opts ...pagination.TokenOption) *IDPager {
+	return &IDPager{codec: pagination.NewTokenCodec[primitive.ObjectID](opts...)}
+}
+
+// Filter returns the filter selecting the documents after the page token.
+func (p *IDPager) Filter(token string) (bson.D, error) {
+	return p.FilterContext(context.Background(), token)
+}
+
+// FilterContext is like Filter, decoding the page token for the context.
+func (p *IDPager) FilterContext(ctx context.Context, token string) (bson.D, error) {
+	if token == "" {
+		return bson.D{}, nil
+	}
+	id, err := p.codec.DecodeContext(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}}}, nil
+}
+
+// FindOptions returns the find options fetching size documents in _id order.
+func (p *IDPager) FindOptions(size int64) *options.FindOptions {
+	return options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(size)
+}
+
+// NextPageToken returns the page token following the document with the last id.
+func (p *IDPager) NextPageToken(last primitive.ObjectID) (string, error) {
+	return p.NextPageTokenContext(context.Background(), last)
+}
+
+// NextPageTokenContext is like NextPageToken, encoding the page token for
+// the context.
+func (p *IDPager) NextPageTokenContext(ctx context.Context, last primitive.ObjectID) (string, error) {
+	return p.codec.EncodeContext(ctx, last)
+}
@@ -0,0 +1,45 @@
+package mongopager
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+func TestFindOptions(t *testing.T) {
+	opts := FindOptions(pagination.PageRange{Offset: 20, Limit: 10}, pagination.SortKey{Column: "created", Desc: true}, pagination.SortKey{Column: "_id"})
+	if *opts.Skip != 20 || *opts.Limit != 10 {
+		t.Fatalf("expected skip %v and limit %v, got %v and %v", 20, 10, *opts.Skip, *opts.Limit)
+	}
+	want := bson.D{{Key: "created", Value: -1}, {Key: "_id", Value: 1}}
+	if !reflect.DeepEqual(opts.Sort, want) {
+		t.Fatalf("expected %v, got %v", want, opts.Sort)
+	}
+}
+
+func TestIDPager(t *testing.T) {
+	p := NewIDPager()
+	if f, err := p.Filter(""); err != nil || len(f) != 0 {
+		t.Fatalf("expected an empty filter, got %v, %v", f, err)
+	}
+	id := primitive.NewObjectID()
+	token, err := p.NextPageToken(id)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	f, err := p.Filter(token)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	want := bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}}}
+	if !reflect.DeepEqual(f, want) {
+		t.Fatalf("expected %v, got %v", want, f)
+	}
+	if _, err := p.Filter("bogus"); err == nil {
+		t.Fatal("expected error for a malformed token, got nil")
+	}
+}
@@ -0,0 +1,105 @@
+package pagination
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+)
+
+// CursorStore stores the cursors starting numbered pages.
+type CursorStore interface {
+	Get(key string) (Cursor, bool)
+	Set(key string, c Cursor)
+}
+
+// NewLRUCursorStore creates an in-memory CursorStore holding up to capacity
+// cursors, evicting the least recently used ones.
+func NewLRUCursorStore(capacity int) CursorStore {
+	return &lruCursorStore{
+		capacity: max(capacity, 1),
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+type lruEntry struct {
+	key    string
+	cursor Cursor
+}
+
+type lruCursorStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// Get returns the cursor stored for key.
+func (s *lruCursorStore) Get(key string) (Cursor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.items[key]
+	if !ok {
+		return Cursor{}, false
+	}
+	s.order.MoveToFront(e)
+	return e.Value.(*lruEntry).cursor, true
+}
+
+// Set stores the cursor for key.
+func (s *lruCursorStore) Set(key string, c Cursor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[key]; ok {
+		e.Value.(*lruEntry).cursor = c
+		s.order.MoveToFront(e)
+		return
+	}
+	s.items[key] = s.order.PushFront(&lruEntry{key: key, cursor: c})
+	for s.order.Len() > s.capacity {
+		e := s.order.Back()
+		s.order.Remove(e)
+		delete(s.items, e.Value.(*lruEntry).key)
+	}
+}
+
+// NumberedPaginator provides numbered page navigation on top of keyset
+// pagination, by remembering the cursor starting each visited page.
+type NumberedPaginator struct {
+	store CursorStore
+}
+
+// NewNumberedPaginator creates a NumberedPaginator keeping cursors in store.
+func NewNumberedPaginator(store CursorStore) *NumberedPaginator {
+	return &NumberedPaginator{store: store}
+}
+
+// Remember records next as the cursor starting the page following page,
+// for the query identified by query, such as a hash of its filter and order.
+func (n *NumberedPaginator) Remember(query string, page int, next Cursor) {
+	if page >= 1 && !next.IsZero() {
+		n.store.Set(pageKey(query, page+1), next)
+	}
+}
+
+// maxPageScan bounds the number of pages CursorFor searches backwards.
+const maxPageScan = 1024
+
+// CursorFor returns the cursor of the closest known page at or before page,
+// and the number of pages to skip from there to reach page. Page 1 always
+// starts at the zero cursor.
+func (n *NumberedPaginator) CursorFor(query string, page int) (c Cursor, skip int, err error) {
+	if page < 1 {
+		return Cursor{}, 0, ErrInvalidPage
+	}
+	for p := page; p > 1 && page-p < maxPageScan; p-- {
+		if c, ok := n.store.Get(pageKey(query, p)); ok {
+			return c, page - p, nil
+		}
+	}
+	return Cursor{}, page - 1, nil
+}
+
+func pageKey(query string, page int) string {
+	return query + "#" + strconv.Itoa(page)
+}
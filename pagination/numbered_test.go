@@ -0,0 +1,57 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNumberedPaginator(t *testing.T) {
+	n := NewNumberedPaginator(NewLRUCursorStore(10))
+	second, third := Cursor{Values: []any{20}}, Cursor{Values: []any{40}}
+
+	if c, skip, err := n.CursorFor("q", 1); err != nil || !c.IsZero() || skip != 0 {
+		t.Fatalf("expected page 1 to start at the zero cursor, got %v, %v, %v", c, skip, err)
+	}
+	n.Remember("q", 1, second)
+	n.Remember("q", 2, third)
+	n.Remember("q", 3, Cursor{})
+
+	tests := []struct {
+		page int
+		want Cursor
+		skip int
+	}{
+		{2, second, 0},
+		{3, third, 0},
+		{5, third, 2},
+	}
+	for _, tt := range tests {
+		c, skip, err := n.CursorFor("q", tt.page)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if len(c.Values) != 1 || c.Values[0] != tt.want.Values[0] || skip != tt.skip {
+			t.Fatalf("expected %v skipping %v for page %v, got %v skipping %v", tt.want, tt.skip, tt.page, c, skip)
+		}
+	}
+	if c, skip, _ := n.CursorFor("other", 3); !c.IsZero() || skip != 2 {
+		t.Fatalf("expected another query to start from page 1, got %v skipping %v", c, skip)
+	}
+	if _, _, err := n.CursorFor("q", 0); !errors.Is(err, ErrInvalidPage) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPage, err)
+	}
+}
+
+func TestLRUCursorStore(t *testing.T) {
+	s := NewLRUCursorStore(2)
+	s.Set("a", Cursor{Values: []any{1}})
+	s.Set("b", Cursor{Values: []any{2}})
+	s.Get("a")
+	s.Set("c", Cursor{Values: []any{3}})
+	if _, ok := s.Get("b"); ok {
+		t.Fatal("expected the least recently used cursor to be evicted")
+	}
+	if c, ok := s.Get("a"); !ok || c.Values[0] != 1 {
+		t.Fatalf("expected %v, got %v, %v", 1, c, ok)
+	}
+}
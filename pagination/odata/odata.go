@@ -0,0 +1,98 @@
+// Package odata parses OData $top, $skip and $orderby query options.
+package odata
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// ErrInvalidQuery is returned when a query option is invalid.
+var ErrInvalidQuery = errors.New("odata: invalid query option")
+
+// Option is parser option.
+type Option func(*parser)
+
+// WithDefaultTop sets the $top used when the query omits it.
+func WithDefaultTop(top int32) Option {
+	return func(p *parser) {
+		if top > 0 {
+			p.defaultTop = top
+		}
+	}
+}
+
+// WithMaxTop sets the maximum $top; larger values are rejected.
+func WithMaxTop(top int32) Option {
+	return func(p *parser) {
+		if top > 0 {
+			p.maxTop = top
+		}
+	}
+}
+
+// WithOrderByFields restricts $orderby to the given fields.
+func WithOrderByFields(fields ...string) Option {
+	return func(p *parser) {
+		p.fields = fields
+	}
+}
+
+type parser struct {
+	defaultTop int32
+	maxTop     int32
+	fields     []string
+}
+
+// Query holds the parsed query options.
+type Query struct {
+	Range   pagination.PageRange
+	OrderBy []pagination.OrderBy
+}
+
+// Parse parses the $top, $skip and $orderby query options of v.
+// A $top of 0 is rejected, since a zero Limit would mean all results.
+func Parse(v url.Values, opts ...Option) (Query, error) {
+	p := &parser{defaultTop: 50, maxTop: 1000}
+	for _, o := range opts {
+		o(p)
+	}
+	top, err := parseInt(v, "$top", p.defaultTop)
+	if err != nil {
+		return Query{}, err
+	}
+	if top == 0 {
+		return Query{}, fmt.Errorf("%w: $top must be positive", ErrInvalidQuery)
+	}
+	if top > p.maxTop {
+		return Query{}, fmt.Errorf("%w: $top must not exceed %d", ErrInvalidQuery, p.maxTop)
+	}
+	skip, err := parseInt(v, "$skip", 0)
+	if err != nil {
+		return Query{}, err
+	}
+	orders, err := pagination.ParseOrderBy(v.Get("$orderby"), p.fields...)
+	if err != nil {
+		return Query{}, fmt.Errorf("%w: $orderby: %w", ErrInvalidQuery, err)
+	}
+	return Query{
+		Range:   pagination.PageRange{Offset: skip, Limit: top},
+		OrderBy: orders,
+	}, nil
+}
+
+func parseInt(v url.Values, key string, def int32) (int32, error) {
+	s := v.Get(key)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || n < 0 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidQuery, key)
+	}
+	return int32(n), nil
+}
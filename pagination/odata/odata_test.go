@@ -0,0 +1,45 @@
+package odata
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(url.Values{"$top": {"20"}, "$skip": {"40"}, "$orderby": {"name desc,id"}}, WithOrderByFields("name", "id"))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if want := (pagination.PageRange{Offset: 40, Limit: 20}); q.Range != want {
+		t.Fatalf("expected %+v, got %+v", want, q.Range)
+	}
+	if len(q.OrderBy) != 2 || q.OrderBy[0].Field != "name" || !q.OrderBy[0].Desc || q.OrderBy[1].Field != "id" || q.OrderBy[1].Desc {
+		t.Fatalf("unexpected order by %+v", q.OrderBy)
+	}
+
+	q, err = Parse(url.Values{}, WithDefaultTop(25))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if want := (pagination.PageRange{Limit: 25}); q.Range != want {
+		t.Fatalf("expected %+v, got %+v", want, q.Range)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, v := range []url.Values{
+		{"$top": {"11"}},
+		{"$top": {"-1"}},
+		{"$top": {"0"}},
+		{"$skip": {"x"}},
+		{"$skip": {"4294967296"}},
+		{"$orderby": {"password"}},
+	} {
+		if _, err := Parse(v, WithMaxTop(10), WithOrderByFields("name")); !errors.Is(err, ErrInvalidQuery) {
+			t.Fatalf("expected %v for %v, got %v", ErrInvalidQuery, v, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidOrderBy is returned when an order_by expression is invalid.
+var ErrInvalidOrderBy = errors.New("invalid order by")
+
+// OrderBy is a single field of an AIP-132 order_by expression.
+type OrderBy struct {
+	Field string
+	Desc  bool
+}
+
+// ParseOrderBy parses an AIP-132 order_by expression such as
+// "create_time desc, name" into its fields. When allowed is not empty,
+// fields outside of it are rejected.
+func ParseOrderBy(s string, allowed ...string) ([]OrderBy, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	orders := make([]OrderBy, 0, len(parts))
+	for _, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("%w: malformed term %q", ErrInvalidOrderBy, strings.TrimSpace(part))
+		}
+		o := OrderBy{Field: fields[0]}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "desc":
+				o.Desc = true
+			case "asc":
+			default:
+				return nil, fmt.Errorf("%w: unknown direction %q", ErrInvalidOrderBy, fields[1])
+			}
+		}
+		if !isFieldPath(o.Field) {
+			return nil, fmt.Errorf("%w: malformed field %q", ErrInvalidOrderBy, o.Field)
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, o.Field) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidOrderBy, o.Field)
+		}
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
+
+// OrderByClause renders the orders as an SQL ORDER BY expression, such as
+// "create_time DESC, name ASC", which can also be passed to GORM's Order.
+func OrderByClause(orders []OrderBy) string {
+	exprs := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if o.Desc {
+			exprs = append(exprs, o.Field+" DESC")
+		} else {
+			exprs = append(exprs, o.Field+" ASC")
+		}
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// SortKeys converts the orders into keyset sort keys.
+func SortKeys(orders []OrderBy) []SortKey {
+	keys := make([]SortKey, 0, len(orders))
+	for _, o := range orders {
+		keys = append(keys, SortKey{Column: o.Field, Desc: o.Desc})
+	}
+	return keys
+}
+
+// isFieldPath reports whether s is a dot-separated path of identifiers.
+func isFieldPath(s string) bool {
+	for _, ident := range strings.Split(s, ".") {
+		if ident == "" {
+			return false
+		}
+		for i, r := range ident {
+			switch {
+			case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			case r >= '0' && r <= '9' && i > 0:
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
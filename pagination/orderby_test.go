@@ -0,0 +1,27 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseOrderBy(t *testing.T) {
+	orders, err := ParseOrderBy("create_time desc, name", "create_time", "name")
+	if err != nil {
+		t.Fatalf("ParseOrderBy returned unexpected error: %v", err)
+	}
+	want := []OrderBy{{Field: "create_time", Desc: true}, {Field: "name"}}
+	if !reflect.DeepEqual(orders, want) {
+		t.Fatalf("expected %v, got %v", want, orders)
+	}
+	if got := OrderByClause(orders); got != "create_time DESC, name ASC" {
+		t.Fatalf("unexpected clause %q", got)
+	}
+
+	for _, s := range []string{"age", "name sideways", "name;drop", "a,,b"} {
+		if _, err := ParseOrderBy(s, "name", "a", "b"); !errors.Is(err, ErrInvalidOrderBy) {
+			t.Fatalf("ParseOrderBy(%q): expected %v, got %v", s, ErrInvalidOrderBy, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package pagination
+
+import "sync"
+
+// Page is a page of results with the tokens of the adjacent pages.
+// TotalSize is -1 when the total is unknown, and TotalEstimated reports
+// that it is an estimate rather than an exact count.
+type Page[T any] struct {
+	Items             []T
+	NextPageToken     string
+	PreviousPageToken string
+	TotalSize         int64
+	TotalEstimated    bool
+
+	total *lazyTotal
+}
+
+// lazyTotal resolves the total of a page once. It is kept behind a pointer
+// so that Page values can be copied.
+type lazyTotal struct {
+	once      sync.Once
+	estimator TotalEstimator
+	total     Total
+	err       error
+	resolved  bool
+}
+
+// TotalKind tells how the total number of results was obtained.
+type TotalKind int
+
+const (
+	// TotalUnknown means that the total is not known.
+	TotalUnknown TotalKind = iota
+	// TotalExact means that the total was counted exactly.
+	TotalExact
+	// TotalEstimated means that the total is an estimate, such as one read
+	// from the query planner statistics.
+	TotalEstimated
+)
+
+// Total is the total number of results.
+type Total struct {
+	Size int64
+	Kind TotalKind
+}
+
+// TotalEstimator provides the total number of results, which may be exact,
+// estimated or unknown when an exact count is too expensive.
+type TotalEstimator interface {
+	EstimateTotal() (Total, error)
+}
+
+// TotalEstimatorFunc is an adapter to use a function as a TotalEstimator.
+type TotalEstimatorFunc func() (Total, error)
+
+// EstimateTotal calls f.
+func (f TotalEstimatorFunc) EstimateTotal() (Total, error) {
+	return f()
+}
+
+// PageOption defines options for NewPage.
+type PageOption func(*pageOptions)
+
+type pageOptions struct {
+	total TotalEstimator
+}
+
+// WithTotalProvider sets the function counting the total number of results.
+// It is invoked lazily by Page.Total, so services can skip the count unless
+// the client requested totals.
+func WithTotalProvider(count func() (int64, error)) PageOption {
+	return WithTotalEstimator(TotalEstimatorFunc(func() (Total, error) {
+		n, err := count()
+		return Total{Size: n, Kind: TotalExact}, err
+	}))
+}
+
+// WithTotalEstimator sets the TotalEstimator consulted lazily by Page.Total.
+func WithTotalEstimator(e TotalEstimator) PageOption {
+	return func(o *pageOptions) {
+		o.total = e
+	}
+}
+
+// NewPage creates a Page of items fetched at currentIndex with pageSize.
+// Callers should fetch up to pageSize+1 items: the extra lookahead item is
+// trimmed, and the next page token is left empty unless it was fetched.
+// The previous page token is left empty on the first page. TotalSize starts
+// at -1, unknown, until it is set or resolved with Total.
+func NewPage[T any](items []T, gen TokenGenerator, currentIndex, pageSize int, opts ...PageOption) *Page[T] {
+	var o pageOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	info := NewPageInfo(gen, currentIndex, pageSize, len(items))
+	page := &Page[T]{
+		Items:             items[:info.EndIndex-info.StartIndex],
+		NextPageToken:     info.NextPageToken,
+		PreviousPageToken: info.PreviousPageToken,
+		TotalSize:         -1,
+	}
+	if o.total != nil {
+		page.total = &lazyTotal{estimator: o.total}
+	}
+	return page
+}
+
+// Total consults the total provider or estimator once, stores the result in
+// TotalSize and TotalEstimated and returns it. An unknown total is stored as
+// -1. Without a provider it returns the current TotalSize.
+func (p *Page[T]) Total() (int64, error) {
+	if p.total == nil {
+		return p.TotalSize, nil
+	}
+	lt := p.total
+	lt.once.Do(func() {
+		lt.total, lt.err = lt.estimator.EstimateTotal()
+		lt.resolved = lt.err == nil
+	})
+	if lt.err != nil {
+		return p.TotalSize, lt.err
+	}
+	switch lt.total.Kind {
+	case TotalUnknown:
+		p.TotalSize, p.TotalEstimated = -1, false
+	case TotalEstimated:
+		p.TotalSize, p.TotalEstimated = lt.total.Size, true
+	default:
+		p.TotalSize, p.TotalEstimated = lt.total.Size, false
+	}
+	return p.TotalSize, nil
+}
+
+// PageTotals holds the totals of paginated results.
+type PageTotals struct {
+	TotalItems int64
+	TotalPages int64
+}
+
+// Totals computes the number of pages of the given size needed to hold
+// totalItems results.
+func Totals(totalItems int64, size int32) PageTotals {
+	t := PageTotals{TotalItems: totalItems}
+	if totalItems > 0 && size > 0 {
+		t.TotalPages = (totalItems + int64(size) - 1) / int64(size)
+	}
+	return t
+}
+
+// PageInfo describes the position of a page within the results.
+// StartIndex is the index of the first item of the page and EndIndex the
+// index following its last item.
+type PageInfo struct {
+	HasNextPage       bool
+	HasPreviousPage   bool
+	StartIndex        int
+	EndIndex          int
+	NextPageToken     string
+	PreviousPageToken string
+}
+
+// NewPageInfo computes the PageInfo of a page starting at currentIndex with
+// pageSize, where fetched counts the items returned when querying pageSize+1
+// rows. The extra row tells whether a next page exists.
+func NewPageInfo(gen TokenGenerator, currentIndex, pageSize, fetched int) PageInfo {
+	n := fetched
+	if pageSize > 0 {
+		n = min(fetched, pageSize)
+	}
+	info := PageInfo{
+		StartIndex: currentIndex,
+		EndIndex:   currentIndex + n,
+	}
+	if pageSize > 0 && fetched > pageSize {
+		info.HasNextPage = true
+		info.NextPageToken = gen.ForIndex(currentIndex + n)
+	}
+	if currentIndex > 0 {
+		info.HasPreviousPage = true
+		info.PreviousPageToken = gen.ForIndex(max(currentIndex-pageSize, 0))
+	}
+	return info
+}
+
+// PageInfoFromRange computes the PageInfo of a page fetched with r, where
+// fetched counts the items returned when querying r.Limit+1 rows. The extra
+// row tells whether a next page exists without a count query. A zero
+// r.Limit fetches all remaining items, so no next page follows.
+func PageInfoFromRange(r PageRange, fetched int) PageInfo {
+	if r.Limit == 0 {
+		return PageInfo{
+			HasPreviousPage: r.Offset > 0,
+			StartIndex:      int(r.Offset),
+			EndIndex:        int(r.Offset) + fetched,
+		}
+	}
+	n := min(fetched, int(r.Limit))
+	return PageInfo{
+		HasNextPage:     fetched > int(r.Limit),
+		HasPreviousPage: r.Offset > 0,
+		StartIndex:      int(r.Offset),
+		EndIndex:        int(r.Offset) + n,
+	}
+}
@@ -0,0 +1,134 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewPageLookahead(t *testing.T) {
+	gen := NewTokenGenerator()
+
+	p := NewPage([]int{1, 2, 3}, gen, 0, 3)
+	if p.NextPageToken != "" || len(p.Items) != 3 {
+		t.Fatalf("expected the last page, got %+v", p)
+	}
+	p = NewPage([]int{1, 2, 3, 4}, gen, 3, 3)
+	if len(p.Items) != 3 {
+		t.Fatalf("expected the lookahead item to be trimmed, got %v", p.Items)
+	}
+	if index, err := gen.GetIndex(p.NextPageToken); err != nil || index != 6 {
+		t.Fatalf("expected %v, got %v, %v", 6, index, err)
+	}
+	if index, err := gen.GetIndex(p.PreviousPageToken); err != nil || index != 0 {
+		t.Fatalf("expected %v, got %v, %v", 0, index, err)
+	}
+}
+
+func TestPageTotalCopy(t *testing.T) {
+	var calls int
+	p := NewPage([]int{1}, NewTokenGenerator(), 0, 10, WithTotalProvider(func() (int64, error) {
+		calls++
+		return 7, nil
+	}))
+	cp := *p
+	if n, err := p.Total(); err != nil || n != 7 {
+		t.Fatalf("expected %v, got %v, %v", 7, n, err)
+	}
+	if n, err := cp.Total(); err != nil || n != 7 {
+		t.Fatalf("expected %v, got %v, %v", 7, n, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected %v call, got %v", 1, calls)
+	}
+}
+
+func TestPageInfoFromRange(t *testing.T) {
+	tests := []struct {
+		r       PageRange
+		fetched int
+		want    PageInfo
+	}{
+		{PageRange{Offset: 10, Limit: 5}, 6, PageInfo{HasNextPage: true, HasPreviousPage: true, StartIndex: 10, EndIndex: 15}},
+		{PageRange{Offset: 0, Limit: 5}, 3, PageInfo{StartIndex: 0, EndIndex: 3}},
+		{PageRange{Offset: 10}, 7, PageInfo{HasPreviousPage: true, StartIndex: 10, EndIndex: 17}},
+	}
+	for _, tt := range tests {
+		if got := PageInfoFromRange(tt.r, tt.fetched); got != tt.want {
+			t.Fatalf("expected %+v, got %+v", tt.want, got)
+		}
+	}
+}
+
+func TestNewPageEmpty(t *testing.T) {
+	p := NewPage([]string{}, NewTokenGenerator(), 0, 10)
+	if len(p.Items) != 0 || p.NextPageToken != "" || p.PreviousPageToken != "" {
+		t.Fatalf("expected an empty last page, got %+v", p)
+	}
+	if n, err := p.Total(); err != nil || n != -1 {
+		t.Fatalf("expected an unknown total without a provider, got %v, %v", n, err)
+	}
+}
+
+func TestNewPagePreviousToken(t *testing.T) {
+	gen := NewTokenGenerator()
+
+	p := NewPage([]int{1, 2}, gen, 3, 5)
+	if index, err := gen.GetIndex(p.PreviousPageToken); err != nil || index != 0 {
+		t.Fatalf("expected the previous page to be clamped at %v, got %v, %v", 0, index, err)
+	}
+	p = NewPage([]int{1, 2}, gen, 12, 5)
+	if index, err := gen.GetIndex(p.PreviousPageToken); err != nil || index != 7 {
+		t.Fatalf("expected %v, got %v, %v", 7, index, err)
+	}
+	if p := NewPage([]int{1, 2}, gen, 0, 5); p.PreviousPageToken != "" {
+		t.Fatalf("expected no previous token on the first page, got %q", p.PreviousPageToken)
+	}
+}
+
+func TestNewPageInfo(t *testing.T) {
+	gen := NewTokenGenerator()
+	tests := []struct {
+		currentIndex, pageSize, fetched int
+		start, end                      int
+		hasNext, hasPrevious            bool
+	}{
+		{0, 10, 11, 0, 10, true, false},
+		{20, 10, 4, 20, 24, false, true},
+		{5, 0, 7, 5, 12, false, true},
+	}
+	for _, tt := range tests {
+		info := NewPageInfo(gen, tt.currentIndex, tt.pageSize, tt.fetched)
+		if info.StartIndex != tt.start || info.EndIndex != tt.end {
+			t.Fatalf("expected [%v, %v), got [%v, %v)", tt.start, tt.end, info.StartIndex, info.EndIndex)
+		}
+		if info.HasNextPage != tt.hasNext || info.HasPreviousPage != tt.hasPrevious {
+			t.Fatalf("unexpected page info %+v", info)
+		}
+		if info.HasNextPage != (info.NextPageToken != "") || info.HasPreviousPage != (info.PreviousPageToken != "") {
+			t.Fatalf("expected the tokens to match the flags, got %+v", info)
+		}
+	}
+}
+
+func TestPageTotalEstimator(t *testing.T) {
+	gen := NewTokenGenerator()
+	estimate := func(total Total, err error) *Page[int] {
+		return NewPage([]int{1}, gen, 0, 10, WithTotalEstimator(TotalEstimatorFunc(func() (Total, error) {
+			return total, err
+		})))
+	}
+
+	p := estimate(Total{Size: 1000, Kind: TotalEstimated}, nil)
+	if n, err := p.Total(); err != nil || n != 1000 || !p.TotalEstimated {
+		t.Fatalf("expected an estimate of %v, got %v, %v, %v", 1000, n, p.TotalEstimated, err)
+	}
+	p = estimate(Total{Kind: TotalUnknown}, nil)
+	if n, err := p.Total(); err != nil || n != -1 || p.TotalEstimated {
+		t.Fatalf("expected an unknown total, got %v, %v, %v", n, p.TotalEstimated, err)
+	}
+	errCount := errors.New("count failed")
+	p = estimate(Total{}, errCount)
+	if _, err := p.Total(); !errors.Is(err, errCount) {
+		t.Fatalf("expected %v, got %v", errCount, err)
+	}
+}
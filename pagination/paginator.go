@@ -1,15 +1,42 @@
 package pagination
 
+import (
+	"errors"
+	"math"
+)
+
+// ErrOffsetOverflow is returned when a page offset overflows its integer type.
+var ErrOffsetOverflow = errors.New("pagination offset overflow")
+
+// ErrInvalidPage is returned when a page number is negative.
+var ErrInvalidPage = errors.New("invalid page")
+
+// ErrEmptyPage is returned under ZeroSizeEmpty when a size of 0 is requested,
+// telling the caller to respond with an empty page without querying.
+var ErrEmptyPage = errors.New("empty page requested")
+
+// ZeroSizePolicy selects how a requested size of 0 is handled.
+type ZeroSizePolicy int
+
+const (
+	// ZeroSizeDefault falls back to the default size.
+	ZeroSizeDefault ZeroSizePolicy = iota
+	// ZeroSizeReject rejects the request with ErrInvalidPageSize.
+	ZeroSizeReject
+	// ZeroSizeEmpty reports ErrEmptyPage so an empty page is returned.
+	ZeroSizeEmpty
+)
+
 // PageRequest defines the interface for requests that contain pagination parameters.
 type PageRequest interface {
 	GetPageNum() int32
 	GetPageSize() int32
 }
 
-// PageRange holds calculated offset and limit values.
-type PageRange struct {
-	Offset int32
-	Limit  int32
+// SkipRequest represents a request that supports the AIP-158 skip field.
+type SkipRequest interface {
+	// GetSkip returns the number of results to skip.
+	GetSkip() int32
 }
 
 // Paginator defines the interface for resolving pagination parameters.
@@ -19,37 +46,209 @@ type Paginator interface {
 	Parse(req PageRequest) PageRange
 }
 
+// CheckedPaginator is a Paginator reporting invalid input with errors.
+// The paginators created by NewPaginator implement it.
+type CheckedPaginator interface {
+	Paginator
+	// ResolveChecked is like Resolve, but rejects negative pages and sizes with
+	// ErrInvalidPage and ErrInvalidPageSize, and overflowing offsets with
+	// ErrOffsetOverflow.
+	ResolveChecked(page, size int32) (PageRange, error)
+	// ParseChecked is like Parse, but validates the request like ResolveChecked.
+	ParseChecked(req PageRequest) (PageRange, error)
+}
+
+// StrictPaginator is a Paginator validating requests without normalizing
+// them. The paginators created by NewPaginator implement it.
+type StrictPaginator interface {
+	Paginator
+	// ParseStrict validates the request without normalizing bad input,
+	// reporting every invalid field in a *ValidationError.
+	ParseStrict(req PageRequest) (PageRange, error)
+}
+
+// Option is paginator option.
+type Option func(*paginator)
+
+// WithDefaultPage overrides the page used when the request omits it.
+func WithDefaultPage(page int32) Option {
+	return func(p *paginator) {
+		if page > 0 {
+			p.Page = page
+		}
+	}
+}
+
+// WithDefaultSize overrides the size used when the request omits it.
+func WithDefaultSize(size int32) Option {
+	return func(p *paginator) {
+		if size > 0 {
+			p.Size = size
+		}
+	}
+}
+
+// WithMaxSize clamps the resolved size to the given maximum.
+func WithMaxSize(size int32) Option {
+	return func(p *paginator) {
+		if size > 0 {
+			p.MaxSize = size
+		}
+	}
+}
+
+// WithAllowUnpaginated makes a size of -1 resolve to a Limit of 0, meaning
+// that all results are returned. It is meant for internal APIs only.
+func WithAllowUnpaginated() Option {
+	return func(p *paginator) {
+		p.AllowAll = true
+	}
+}
+
+// WithZeroSize sets the policy for a requested size of 0. The policy is
+// enforced by ResolveChecked and ParseChecked; Resolve and Parse, which cannot
+// report errors, always fall back to the default size.
+func WithZeroSize(policy ZeroSizePolicy) Option {
+	return func(p *paginator) {
+		p.ZeroSize = policy
+	}
+}
+
+// WithPageTokens makes ParseStrict start from the position of the page token
+// of requests implementing TokenRequest, as generated by gen.
+func WithPageTokens(gen TokenGenerator) Option {
+	return func(p *paginator) {
+		p.Tokens = gen
+	}
+}
+
 // NewPaginator creates a new Pagination instance with default page and size.
-func NewPaginator(defaultPage, defaultSize int32) Paginator {
-	return &paginator{
+func NewPaginator(defaultPage, defaultSize int32, opts ...Option) Paginator {
+	p := &paginator{
 		Page: defaultPage,
 		Size: defaultSize,
 	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
 }
 
 // paginator holds default paginator settings.
 type paginator struct {
-	Page int32
-	Size int32
+	Page     int32
+	Size     int32
+	MaxSize  int32
+	AllowAll bool
+	ZeroSize ZeroSizePolicy
+	Tokens   TokenGenerator
 }
 
 // Resolve calculates the offset and limit based on the provided page and size,
-// applying defaults when page/size are <= 0.
+// applying defaults when page/size are <= 0 and clamping size to the maximum.
+// Offsets overflowing int32 saturate at math.MaxInt32.
 func (p *paginator) Resolve(page, size int32) PageRange {
-	if page <= 0 {
-		page = p.Page
+	r, err := resolveRange(p, page, size)
+	if err != nil {
+		r.Offset = math.MaxInt32
 	}
-	if size <= 0 {
-		size = p.Size
+	return r
+}
+
+// Resolve64 calculates the offset and limit based on the provided page and size
+// like Resolve, using overflow-checked int64 arithmetic.
+//
+// Deprecated: use ResolveRange[int64].
+func (p *paginator) Resolve64(page, size int64) (PageRange64, error) {
+	return resolveRange(p, page, size)
+}
+
+// ResolveChecked calculates the offset and limit like Resolve, returning an
+// error instead of a negative or overflowed range for invalid input.
+func (p *paginator) ResolveChecked(page, size int32) (PageRange, error) {
+	if page < 0 {
+		return PageRange{}, ErrInvalidPage
+	}
+	if size < 0 && !(p.AllowAll && size == -1) {
+		return PageRange{}, ErrInvalidPageSize
 	}
-	offset := (page - 1) * size
-	return PageRange{
-		Offset: offset,
-		Limit:  size,
+	if size == 0 {
+		switch p.ZeroSize {
+		case ZeroSizeReject:
+			return PageRange{}, ErrInvalidPageSize
+		case ZeroSizeEmpty:
+			return PageRange{}, ErrEmptyPage
+		}
 	}
+	r, err := resolveRange(p, page, size)
+	if err != nil {
+		return PageRange{}, err
+	}
+	return r, nil
 }
 
 // Parse extracts pagination parameters from a PageRequest and resolves them.
+// Requests implementing TokenRequest start at the position of a valid page
+// token when the paginator has page tokens, and requests implementing
+// SkipRequest skip the given number of results from the start of the page.
+// Offsets overflowing int32 saturate at math.MaxInt32.
 func (p *paginator) Parse(req PageRequest) PageRange {
-	return p.Resolve(req.GetPageNum(), req.GetPageSize())
+	r := p.Resolve(req.GetPageNum(), req.GetPageSize())
+	if offset, ok, err := p.tokenOffset(req); ok && err == nil {
+		r.Offset = offset
+	}
+	if sr, ok := req.(SkipRequest); ok && sr.GetSkip() > 0 {
+		if int64(r.Offset)+int64(sr.GetSkip()) > math.MaxInt32 {
+			r.Offset = math.MaxInt32
+		} else {
+			r.Offset += sr.GetSkip()
+		}
+	}
+	return r
+}
+
+// ParseChecked extracts pagination parameters from a PageRequest and resolves
+// them like ResolveChecked, applying the page token and skip like Parse.
+func (p *paginator) ParseChecked(req PageRequest) (PageRange, error) {
+	r, err := p.ResolveChecked(req.GetPageNum(), req.GetPageSize())
+	if err != nil {
+		return PageRange{}, err
+	}
+	offset, ok, err := p.tokenOffset(req)
+	if err != nil {
+		return PageRange{}, err
+	}
+	if ok {
+		r.Offset = offset
+	}
+	if sr, ok := req.(SkipRequest); ok {
+		if sr.GetSkip() < 0 {
+			return PageRange{}, ErrInvalidSkip
+		}
+		if int64(r.Offset)+int64(sr.GetSkip()) > math.MaxInt32 {
+			return PageRange{}, ErrOffsetOverflow
+		}
+		r.Offset += sr.GetSkip()
+	}
+	return r, nil
+}
+
+// tokenOffset returns the position of the page token of req, reporting
+// whether the request carries a token the paginator can decode.
+func (p *paginator) tokenOffset(req PageRequest) (int32, bool, error) {
+	tr, ok := req.(TokenRequest)
+	if !ok || p.Tokens == nil || tr.GetPageToken() == "" {
+		return 0, false, nil
+	}
+	index, err := p.Tokens.GetIndex(tr.GetPageToken())
+	if err != nil {
+		return 0, false, err
+	}
+	if index < 0 {
+		return 0, false, ErrInvalidToken
+	}
+	if index > math.MaxInt32 {
+		return 0, false, ErrOffsetOverflow
+	}
+	return int32(index), true, nil
 }
@@ -0,0 +1,215 @@
+package pagination
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestPaginatorResolve(t *testing.T) {
+	p := NewPaginator(1, 20, WithMaxSize(100))
+
+	tests := []struct {
+		page, size int32
+		want       PageRange
+	}{
+		{0, 0, PageRange{Offset: 0, Limit: 20}},
+		{3, 10, PageRange{Offset: 20, Limit: 10}},
+		{2, 1000000, PageRange{Offset: 100, Limit: 100}},
+	}
+	for _, tt := range tests {
+		if got := p.Resolve(tt.page, tt.size); got != tt.want {
+			t.Fatalf("Resolve(%d, %d) = %+v, want %+v", tt.page, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestResolveRangeOverflow(t *testing.T) {
+	p := NewPaginator(1, 20)
+
+	r, err := ResolveRange[int64](p, 1<<40, 1000)
+	if err != nil {
+		t.Fatalf("ResolveRange returned unexpected error: %v", err)
+	}
+	if r.Offset != (1<<40-1)*1000 {
+		t.Fatalf("unexpected offset %d", r.Offset)
+	}
+	if _, err := ResolveRange[int64](p, math.MaxInt64, 2); !errors.Is(err, ErrOffsetOverflow) {
+		t.Fatalf("expected %v, got %v", ErrOffsetOverflow, err)
+	}
+	if _, err := ResolveRange[int32](p, 1<<20, 1<<12); !errors.Is(err, ErrOffsetOverflow) {
+		t.Fatalf("expected %v, got %v", ErrOffsetOverflow, err)
+	}
+}
+
+func TestPaginatorResolveChecked(t *testing.T) {
+	p := NewPaginator(1, 20).(CheckedPaginator)
+
+	if _, err := p.ResolveChecked(-1, 10); !errors.Is(err, ErrInvalidPage) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPage, err)
+	}
+	if _, err := p.ResolveChecked(1, -1); !errors.Is(err, ErrInvalidPageSize) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPageSize, err)
+	}
+	if _, err := p.ResolveChecked(math.MaxInt32, math.MaxInt32); !errors.Is(err, ErrOffsetOverflow) {
+		t.Fatalf("expected %v, got %v", ErrOffsetOverflow, err)
+	}
+}
+
+func TestPaginatorAllowUnpaginated(t *testing.T) {
+	if got := NewPaginator(1, 20).Resolve(1, -1); got.Limit != 20 {
+		t.Fatalf("expected default limit 20, got %d", got.Limit)
+	}
+	p := NewPaginator(1, 20, WithAllowUnpaginated())
+	if got := p.Resolve(3, -1); got != (PageRange{}) {
+		t.Fatalf("expected unbounded range, got %+v", got)
+	}
+	if got, err := p.(CheckedPaginator).ResolveChecked(3, -1); err != nil || got != (PageRange{}) {
+		t.Fatalf("expected unbounded range, got %+v (%v)", got, err)
+	}
+}
+
+func TestPaginatorFromConfigEnv(t *testing.T) {
+	t.Setenv("USERS_DEFAULT_SIZE", "10")
+	t.Setenv("USERS_MAX_SIZE", "50")
+
+	cfg, err := Config{DefaultSize: 30}.FromEnv("users")
+	if err != nil {
+		t.Fatalf("FromEnv returned unexpected error: %v", err)
+	}
+	p := NewPaginatorFromConfig(cfg)
+	if got := p.Resolve(0, 0); got != (PageRange{Offset: 0, Limit: 10}) {
+		t.Fatalf("expected limit 10, got %+v", got)
+	}
+	if got := p.Resolve(1, 500); got.Limit != 50 {
+		t.Fatalf("expected limit 50, got %d", got.Limit)
+	}
+
+	t.Setenv("USERS_ALLOW_ALL", "maybe")
+	if _, err := cfg.FromEnv("users"); err == nil {
+		t.Fatalf("expected error for invalid USERS_ALLOW_ALL")
+	}
+}
+
+type testRequest struct {
+	page, size, skip int32
+	token            string
+}
+
+func (r testRequest) GetPageNum() int32    { return r.page }
+func (r testRequest) GetPageSize() int32   { return r.size }
+func (r testRequest) GetSkip() int32       { return r.skip }
+func (r testRequest) GetPageToken() string { return r.token }
+
+func TestPaginatorParseSkip(t *testing.T) {
+	p := NewPaginator(1, 20).(CheckedPaginator)
+
+	if r := p.Parse(testRequest{page: 2, size: 10, skip: 5}); r.Offset != 15 {
+		t.Fatalf("expected %v, got %v", 15, r.Offset)
+	}
+	if r := p.Parse(testRequest{page: math.MaxInt32, size: 10, skip: 5}); r.Offset != math.MaxInt32 {
+		t.Fatalf("expected %v, got %v", int32(math.MaxInt32), r.Offset)
+	}
+	if _, err := p.ParseChecked(testRequest{page: math.MaxInt32 / 10, size: 10, skip: 100}); !errors.Is(err, ErrOffsetOverflow) {
+		t.Fatalf("expected %v, got %v", ErrOffsetOverflow, err)
+	}
+}
+
+func TestPaginatorParseToken(t *testing.T) {
+	tokens := NewTokenGenerator()
+	p := NewPaginator(1, 20, WithPageTokens(tokens)).(CheckedPaginator)
+
+	req := testRequest{size: 10, skip: 5, token: tokens.ForIndex(40)}
+	if r := p.Parse(req); r.Offset != 45 || r.Limit != 10 {
+		t.Fatalf("expected %+v, got %+v", PageRange{Offset: 45, Limit: 10}, r)
+	}
+	r, err := p.ParseChecked(req)
+	if err != nil || r.Offset != 45 {
+		t.Fatalf("expected %v, got %v, %v", 45, r.Offset, err)
+	}
+	req.token = "bogus"
+	if r := p.Parse(req); r.Offset != 5 {
+		t.Fatalf("expected %v, got %v", 5, r.Offset)
+	}
+	if _, err := p.ParseChecked(req); err == nil {
+		t.Fatal("expected error for malformed token, got nil")
+	}
+}
+
+func TestPaginatorParseStrict(t *testing.T) {
+	tokens := NewTokenGenerator()
+	p := NewPaginator(1, 20, WithMaxSize(50), WithPageTokens(tokens))
+
+	r, err := ParseStrict(p, testRequest{page: 3, size: 10, skip: 2})
+	if err != nil || r != (PageRange{Offset: 22, Limit: 10}) {
+		t.Fatalf("expected %+v, got %+v, %v", PageRange{Offset: 22, Limit: 10}, r, err)
+	}
+	r, err = ParseStrict(p, testRequest{size: 10, token: tokens.ForIndex(40)})
+	if err != nil || r.Offset != 40 {
+		t.Fatalf("expected %v, got %v, %v", 40, r.Offset, err)
+	}
+
+	_, err = ParseStrict(p, testRequest{page: -1, size: 51, skip: -1, token: "bogus"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	var fields []string
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	if want := []string{"page_num", "page_size", "skip", "page_token"}; !slices.Equal(fields, want) {
+		t.Fatalf("expected %v, got %v", want, fields)
+	}
+
+	_, err = ParseStrict(p, testRequest{page: math.MaxInt32, size: 50})
+	if !errors.As(err, &verr) || verr.Violations[0].Field != "page_num" {
+		t.Fatalf("expected an offset violation, got %v", err)
+	}
+	if _, err := ParseStrict(NewPaginator(1, 20, WithZeroSize(ZeroSizeEmpty)), testRequest{}); !errors.Is(err, ErrEmptyPage) {
+		t.Fatalf("expected %v, got %v", ErrEmptyPage, err)
+	}
+}
+
+type plainPaginator struct{}
+
+func (plainPaginator) Resolve(page, size int32) PageRange { return PageRange{Limit: size} }
+func (plainPaginator) Parse(req PageRequest) PageRange    { return PageRange{Limit: req.GetPageSize()} }
+
+func TestParseStrictPlainPaginator(t *testing.T) {
+	r, err := ParseStrict(plainPaginator{}, testRequest{size: 7})
+	if err != nil || r.Limit != 7 {
+		t.Fatalf("expected %v, got %v, %v", 7, r.Limit, err)
+	}
+	if r, err := ResolveRange[int64](plainPaginator{}, 1, 7); err != nil || r.Limit != 7 {
+		t.Fatalf("expected %v, got %v, %v", 7, r.Limit, err)
+	}
+}
+
+func TestPaginatorZeroSize(t *testing.T) {
+	tests := []struct {
+		policy ZeroSizePolicy
+		want   error
+	}{
+		{ZeroSizeDefault, nil},
+		{ZeroSizeReject, ErrInvalidPageSize},
+		{ZeroSizeEmpty, ErrEmptyPage},
+	}
+	for _, tt := range tests {
+		p := NewPaginator(1, 20, WithZeroSize(tt.policy))
+		r, err := p.(CheckedPaginator).ResolveChecked(1, 0)
+		if !errors.Is(err, tt.want) {
+			t.Fatalf("expected %v for policy %v, got %v", tt.want, tt.policy, err)
+		}
+		if err == nil && r.Limit != 20 {
+			t.Fatalf("expected default limit %v, got %v", 20, r.Limit)
+		}
+		if r := p.Resolve(1, 0); r.Limit != 20 {
+			t.Fatalf("expected Resolve to fall back to %v for policy %v, got %v", 20, tt.policy, r.Limit)
+		}
+		if _, err := p.(CheckedPaginator).ParseChecked(testRequest{page: 1}); !errors.Is(err, tt.want) {
+			t.Fatalf("expected %v for policy %v, got %v", tt.want, tt.policy, err)
+		}
+	}
+}
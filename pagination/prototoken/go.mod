@@ -0,0 +1,10 @@
+module github.com/go-kratos/kit/pagination/prototoken
+
+go 1.24.0
+
+replace github.com/go-kratos/kit => ../..
+
+require (
+	github.com/go-kratos/kit v0.0.0
+	google.golang.org/protobuf v1.36.12
+)
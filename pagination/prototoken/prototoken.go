@@ -0,0 +1,46 @@
+// Package prototoken encodes protobuf messages into opaque page tokens.
+package prototoken
+
+import (
+	"encoding/base64"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// NewCodec creates a TokenCodec that serializes messages of type M with
+// proto.Marshal and base64url. Unknown fields are tolerated on decode, so
+// token schemas can evolve across service versions.
+func NewCodec[M proto.Message]() pagination.TokenCodec[M] {
+	return codec[M]{}
+}
+
+type codec[M proto.Message] struct{}
+
+// Encode encodes the message into a page token.
+func (codec[M]) Encode(m M) (string, error) {
+	bs, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
+
+// Decode decodes the message from a page token. An empty message is
+// returned for an empty token.
+func (codec[M]) Decode(token string) (M, error) {
+	var zero M
+	m := zero.ProtoReflect().Type().New().Interface().(M)
+	if token == "" {
+		return m, nil
+	}
+	bs, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return zero, pagination.ErrInvalidToken
+	}
+	if err := proto.Unmarshal(bs, m); err != nil {
+		return zero, pagination.ErrInvalidToken
+	}
+	return m, nil
+}
@@ -0,0 +1,23 @@
+package prototoken
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	c := NewCodec[*wrapperspb.StringValue]()
+
+	token, err := c.Encode(wrapperspb.String("cursor"))
+	if err != nil {
+		t.Fatalf("Encode returned unexpected error: %v", err)
+	}
+	m, err := c.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode returned unexpected error: %v", err)
+	}
+	if m.GetValue() != "cursor" {
+		t.Fatalf("expected cursor, got %q", m.GetValue())
+	}
+}
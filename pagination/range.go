@@ -0,0 +1,81 @@
+package pagination
+
+import "math"
+
+// Integer is the constraint for the offset and limit of a Range.
+type Integer interface {
+	~int | ~int32 | ~int64
+}
+
+// Range holds calculated offset and limit values. A Limit of 0 means that
+// all results are selected.
+type Range[I Integer] struct {
+	Offset I
+	Limit  I
+}
+
+// PageRange holds calculated offset and limit values as int32, matching
+// the page fields of most APIs.
+type PageRange = Range[int32]
+
+// PageRange64 holds calculated offset and limit values for deep offsets
+// that do not fit in int32.
+//
+// Deprecated: use Range[int64].
+type PageRange64 = Range[int64]
+
+// ResolveRange calculates the offset and limit of page and size with the
+// defaults and limits of p, in the integer type I. Offsets overflowing I are
+// reported with ErrOffsetOverflow. Paginators not created by NewPaginator are
+// resolved with their Resolve64 method if any, and with Resolve otherwise.
+func ResolveRange[I Integer](p Paginator, page, size I) (Range[I], error) {
+	switch p := p.(type) {
+	case *paginator:
+		return resolveRange(p, page, size)
+	case interface {
+		Resolve64(page, size int64) (PageRange64, error)
+	}:
+		r, err := p.Resolve64(int64(page), int64(size))
+		if err != nil {
+			return Range[I]{}, err
+		}
+		if int64(I(r.Offset)) != r.Offset {
+			return Range[I]{}, ErrOffsetOverflow
+		}
+		return Range[I]{Offset: I(r.Offset), Limit: I(r.Limit)}, nil
+	}
+	if int64(int32(page)) != int64(page) || int64(int32(size)) != int64(size) {
+		return Range[I]{}, ErrOffsetOverflow
+	}
+	r := p.Resolve(int32(page), int32(size))
+	return Range[I]{Offset: I(r.Offset), Limit: I(r.Limit)}, nil
+}
+
+// resolveRange applies the paginator defaults and limits to page and size
+// and computes the range. On overflow, the returned range holds the
+// resolved limit along with ErrOffsetOverflow.
+func resolveRange[I Integer](p *paginator, page, size I) (Range[I], error) {
+	if p.AllowAll && size == -1 {
+		return Range[I]{}, nil
+	}
+	if page <= 0 {
+		page = I(p.Page)
+	}
+	if size <= 0 {
+		size = I(p.Size)
+	}
+	if p.MaxSize > 0 && int64(size) > int64(p.MaxSize) {
+		size = I(p.MaxSize)
+	}
+	r := Range[I]{Limit: size}
+	pages, n := int64(page)-1, int64(size)
+	if pages > 0 && n > 0 && pages > math.MaxInt64/n {
+		return r, ErrOffsetOverflow
+	}
+	offset := pages * n
+	if int64(I(offset)) != offset {
+		return r, ErrOffsetOverflow
+	}
+	r.Offset = I(offset)
+	return r, nil
+}
@@ -0,0 +1,43 @@
+package pagination
+
+import "github.com/go-kratos/kit/container/maps"
+
+// Registry holds the paginators of named resources, so that the limits of
+// all resources are managed in one place.
+type Registry struct {
+	paginators maps.Map[string, Paginator]
+	fallback   Paginator
+}
+
+// NewRegistry creates a Registry returning fallback for unknown resources.
+func NewRegistry(fallback Paginator) *Registry {
+	return &Registry{fallback: fallback}
+}
+
+// Register sets the paginator of the named resource.
+func (r *Registry) Register(name string, p Paginator) *Registry {
+	r.paginators.Store(name, p)
+	return r
+}
+
+// Load registers a paginator for each named resource config.
+func (r *Registry) Load(configs map[string]Config) *Registry {
+	for name, c := range configs {
+		r.Register(name, NewPaginatorFromConfig(c))
+	}
+	return r
+}
+
+// Lookup returns the paginator of the named resource, if registered.
+func (r *Registry) Lookup(name string) (Paginator, bool) {
+	return r.paginators.Load(name)
+}
+
+// Get returns the paginator of the named resource, or the fallback paginator
+// when the resource is not registered.
+func (r *Registry) Get(name string) Paginator {
+	if p, ok := r.paginators.Load(name); ok {
+		return p
+	}
+	return r.fallback
+}
@@ -0,0 +1,26 @@
+package pagination
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	fallback := NewPaginator(1, 20)
+	r := NewRegistry(fallback).
+		Register("users", NewPaginator(1, 10)).
+		Load(map[string]Config{"orders": {DefaultPage: 1, DefaultSize: 50, MaxSize: 100}})
+
+	if got := r.Get("users").Resolve(0, 0); got.Limit != 10 {
+		t.Fatalf("expected %v, got %v", 10, got.Limit)
+	}
+	if got := r.Get("orders").Resolve(0, 1000); got.Limit != 100 {
+		t.Fatalf("expected the configured max size %v, got %v", 100, got.Limit)
+	}
+	if r.Get("unknown") != fallback {
+		t.Fatal("expected the fallback paginator for an unknown resource")
+	}
+	if _, ok := r.Lookup("unknown"); ok {
+		t.Fatal("expected Lookup to report an unknown resource")
+	}
+	if _, ok := r.Lookup("users"); !ok {
+		t.Fatal("expected Lookup to find a registered resource")
+	}
+}
@@ -0,0 +1,188 @@
+// Package relay implements GraphQL Relay cursor connections.
+package relay
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// ErrInvalidArgs is returned when the connection arguments are invalid.
+var ErrInvalidArgs = errors.New("relay: invalid connection arguments")
+
+const offsetPrefix = "arrayconnection:"
+
+// Args holds the Relay connection arguments.
+type Args struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// Edge is an edge of a connection.
+type Edge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// PageInfo is the Relay page info of a connection.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// Connection is a Relay connection of nodes of type T.
+type Connection[T any] struct {
+	Edges      []Edge[T] `json:"edges"`
+	PageInfo   PageInfo  `json:"pageInfo"`
+	TotalCount int       `json:"totalCount"`
+}
+
+// OffsetToCursor returns the opaque cursor of the given offset, in the
+// format used by the graphql-relay reference implementation.
+func OffsetToCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(offsetPrefix + strconv.Itoa(offset)))
+}
+
+// CursorToOffset returns the offset of the given cursor.
+func CursorToOffset(cursor string) (int, error) {
+	bs, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(bs), offsetPrefix) {
+		return 0, pagination.ErrInvalidToken
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(bs), offsetPrefix))
+	if err != nil || offset < 0 {
+		return 0, pagination.ErrInvalidToken
+	}
+	return offset, nil
+}
+
+// Resolve converts the arguments into the range of a list of total nodes.
+// When the arguments select no nodes, it returns the empty range at the
+// start offset with pagination.ErrEmptyPage, since a zero Limit would mean
+// all nodes; callers skip the fetch and pass the range to NewConnection.
+func Resolve(args Args, total int) (pagination.PageRange, error) {
+	start, end := 0, total
+	if args.After != nil {
+		after, err := CursorToOffset(*args.After)
+		if err != nil {
+			return pagination.PageRange{}, err
+		}
+		start = max(start, after+1)
+	}
+	if args.Before != nil {
+		before, err := CursorToOffset(*args.Before)
+		if err != nil {
+			return pagination.PageRange{}, err
+		}
+		end = min(end, before)
+	}
+	if args.First != nil {
+		if *args.First < 0 {
+			return pagination.PageRange{}, ErrInvalidArgs
+		}
+		end = min(end, start+*args.First)
+	}
+	if args.Last != nil {
+		if *args.Last < 0 {
+			return pagination.PageRange{}, ErrInvalidArgs
+		}
+		start = max(start, end-*args.Last)
+	}
+	if start >= end {
+		return pagination.PageRange{Offset: int32(min(start, end))}, pagination.ErrEmptyPage
+	}
+	return pagination.PageRange{Offset: int32(start), Limit: int32(end - start)}, nil
+}
+
+// NewConnection creates the connection of the nodes fetched with r from a
+// list of total nodes, using offset cursors.
+func NewConnection[T any](nodes []T, r pagination.PageRange, total int) *Connection[T] {
+	c := &Connection[T]{
+		Edges:      make([]Edge[T], 0, len(nodes)),
+		TotalCount: total,
+	}
+	for i, node := range nodes {
+		c.Edges = append(c.Edges, Edge[T]{Node: node, Cursor: OffsetToCursor(int(r.Offset) + i)})
+	}
+	c.PageInfo.HasPreviousPage = r.Offset > 0
+	c.PageInfo.HasNextPage = int(r.Offset)+len(nodes) < total
+	if len(c.Edges) > 0 {
+		c.PageInfo.StartCursor = &c.Edges[0].Cursor
+		c.PageInfo.EndCursor = &c.Edges[len(c.Edges)-1].Cursor
+	}
+	return c
+}
+
+// ResolveCursor converts the arguments into a keyset cursor decoded by p and
+// the number of nodes to fetch. backward reports that last/before were used,
+// in which case the caller must fetch in reverse order and reverse the nodes.
+// A limit of 0 means all nodes; a first or last of 0 is reported as
+// pagination.ErrEmptyPage along with the decoded cursor.
+func ResolveCursor(ctx context.Context, args Args, p pagination.ContextCursorPaginator) (c pagination.Cursor, limit int, backward bool, err error) {
+	switch {
+	case args.First != nil && args.Last != nil:
+		return c, 0, false, ErrInvalidArgs
+	case args.Last != nil:
+		if *args.Last < 0 {
+			return c, 0, false, ErrInvalidArgs
+		}
+		if args.Before != nil {
+			if c, err = p.GetCursorContext(ctx, *args.Before); err != nil {
+				return c, 0, true, err
+			}
+		}
+		if *args.Last == 0 {
+			return c, 0, true, pagination.ErrEmptyPage
+		}
+		return c, *args.Last, true, nil
+	default:
+		if args.First != nil && *args.First < 0 {
+			return c, 0, false, ErrInvalidArgs
+		}
+		if args.After != nil {
+			if c, err = p.GetCursorContext(ctx, *args.After); err != nil {
+				return c, 0, false, err
+			}
+		}
+		if args.First != nil {
+			if *args.First == 0 {
+				return c, 0, false, pagination.ErrEmptyPage
+			}
+			limit = *args.First
+		}
+		return c, limit, false, nil
+	}
+}
+
+// NewCursorConnection creates the connection of the nodes fetched after the
+// cursor, using the keyset cursors p generates from the values extract returns
+// for each node. hasMore reports whether more nodes follow in the direction of
+// the fetch.
+func NewCursorConnection[T any](ctx context.Context, nodes []T, p pagination.ContextCursorPaginator, extract func(T) []any, backward, hasMore bool) (*Connection[T], error) {
+	c := &Connection[T]{Edges: make([]Edge[T], 0, len(nodes))}
+	for _, node := range nodes {
+		cursor, err := p.ForCursorContext(ctx, pagination.Cursor{Values: extract(node)})
+		if err != nil {
+			return nil, err
+		}
+		c.Edges = append(c.Edges, Edge[T]{Node: node, Cursor: cursor})
+	}
+	if backward {
+		c.PageInfo.HasPreviousPage = hasMore
+	} else {
+		c.PageInfo.HasNextPage = hasMore
+	}
+	if len(c.Edges) > 0 {
+		c.PageInfo.StartCursor = &c.Edges[0].Cursor
+		c.PageInfo.EndCursor = &c.Edges[len(c.Edges)-1].Cursor
+	}
+	return c, nil
+}
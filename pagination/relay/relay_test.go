@@ -0,0 +1,118 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+func TestCursorOffset(t *testing.T) {
+	if got := OffsetToCursor(5); got != "YXJyYXljb25uZWN0aW9uOjU=" {
+		t.Fatalf("expected the graphql-relay cursor, got %q", got)
+	}
+	if offset, err := CursorToOffset(OffsetToCursor(42)); err != nil || offset != 42 {
+		t.Fatalf("expected %v, got %v, %v", 42, offset, err)
+	}
+	for _, bad := range []string{"!", "Zm9v", OffsetToCursor(-1)} {
+		if _, err := CursorToOffset(bad); !errors.Is(err, pagination.ErrInvalidToken) {
+			t.Fatalf("expected %v for %q, got %v", pagination.ErrInvalidToken, bad, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	two, three := 2, 3
+	after, before := OffsetToCursor(1), OffsetToCursor(8)
+	tests := []struct {
+		args Args
+		want pagination.PageRange
+	}{
+		{Args{}, pagination.PageRange{Offset: 0, Limit: 10}},
+		{Args{First: &three}, pagination.PageRange{Offset: 0, Limit: 3}},
+		{Args{First: &three, After: &after}, pagination.PageRange{Offset: 2, Limit: 3}},
+		{Args{Last: &two}, pagination.PageRange{Offset: 8, Limit: 2}},
+		{Args{Last: &two, Before: &before}, pagination.PageRange{Offset: 6, Limit: 2}},
+		{Args{After: &after, Before: &before}, pagination.PageRange{Offset: 2, Limit: 6}},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.args, 10)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected %+v, got %+v", tt.want, got)
+		}
+	}
+	negative := -1
+	if _, err := Resolve(Args{First: &negative}, 10); !errors.Is(err, ErrInvalidArgs) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgs, err)
+	}
+}
+
+func TestResolveEmpty(t *testing.T) {
+	zero := 0
+	after, before := OffsetToCursor(4), OffsetToCursor(5)
+	tests := []struct {
+		args Args
+		want pagination.PageRange
+	}{
+		{Args{First: &zero}, pagination.PageRange{Offset: 0}},
+		{Args{First: &zero, After: &after}, pagination.PageRange{Offset: 5}},
+		{Args{After: &after, Before: &before}, pagination.PageRange{Offset: 5}},
+		{Args{After: &before, Before: &after}, pagination.PageRange{Offset: 4}},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.args, 10)
+		if !errors.Is(err, pagination.ErrEmptyPage) {
+			t.Fatalf("expected %v, got %v", pagination.ErrEmptyPage, err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected %+v, got %+v", tt.want, got)
+		}
+	}
+}
+
+func TestNewConnection(t *testing.T) {
+	c := NewConnection([]string{"b", "c"}, pagination.PageRange{Offset: 1, Limit: 2}, 4)
+	if len(c.Edges) != 2 || c.Edges[0].Cursor != OffsetToCursor(1) || c.Edges[1].Cursor != OffsetToCursor(2) {
+		t.Fatalf("unexpected edges %+v", c.Edges)
+	}
+	if !c.PageInfo.HasNextPage || !c.PageInfo.HasPreviousPage || c.TotalCount != 4 {
+		t.Fatalf("unexpected page info %+v", c.PageInfo)
+	}
+	if *c.PageInfo.StartCursor != c.Edges[0].Cursor || *c.PageInfo.EndCursor != c.Edges[1].Cursor {
+		t.Fatalf("unexpected cursors %+v", c.PageInfo)
+	}
+}
+
+func TestCursorConnection(t *testing.T) {
+	p := pagination.NewCursorPaginator([]pagination.SortKey{{Column: "id"}})
+	extract := func(id int) []any { return []any{id} }
+
+	ctx := context.Background()
+
+	c, err := NewCursorConnection(ctx, []int{1, 2}, p, extract, false, true)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !c.PageInfo.HasNextPage || c.PageInfo.HasPreviousPage {
+		t.Fatalf("unexpected page info %+v", c.PageInfo)
+	}
+	first := 2
+	cursor, limit, backward, err := ResolveCursor(ctx, Args{First: &first, After: c.PageInfo.EndCursor}, p)
+	if err != nil || limit != 2 || backward {
+		t.Fatalf("expected a forward fetch of %v, got %v, %v, %v", 2, limit, backward, err)
+	}
+	if len(cursor.Values) != 1 || cursor.Values[0] != int64(2) {
+		t.Fatalf("expected cursor %v, got %v", 2, cursor.Values)
+	}
+	if _, _, _, err := ResolveCursor(ctx, Args{First: &first, Last: &first}, p); !errors.Is(err, ErrInvalidArgs) {
+		t.Fatalf("expected %v, got %v", ErrInvalidArgs, err)
+	}
+	zero := 0
+	if _, _, _, err := ResolveCursor(ctx, Args{First: &zero}, p); !errors.Is(err, pagination.ErrEmptyPage) {
+		t.Fatalf("expected %v, got %v", pagination.ErrEmptyPage, err)
+	}
+}
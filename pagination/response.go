@@ -0,0 +1,133 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// ErrInvalidPageSize is returned when a page size is negative.
+var ErrInvalidPageSize = errors.New("invalid page size")
+
+// ErrInvalidSkip is returned when a skip value is negative.
+var ErrInvalidSkip = errors.New("invalid skip")
+
+// ListRequest represents an AIP-158 list request.
+type ListRequest interface {
+	TokenRequest
+	// GetPageSize returns the page size of the request.
+	GetPageSize() int32
+}
+
+// ResponseOption defines options for the ResponseBuilder.
+type ResponseOption func(*responseBuilder)
+
+// WithDefaultPageSize sets the page size used when the request omits it.
+func WithDefaultPageSize(size int32) ResponseOption {
+	return func(b *responseBuilder) {
+		if size > 0 {
+			b.defaultSize = size
+		}
+	}
+}
+
+// WithMaxPageSize sets the upper bound that larger page sizes are coerced to.
+func WithMaxPageSize(size int32) ResponseOption {
+	return func(b *responseBuilder) {
+		if size > 0 {
+			b.maxSize = size
+		}
+	}
+}
+
+// WithSkipSupport honors the skip field of requests implementing SkipRequest.
+func WithSkipSupport() ResponseOption {
+	return func(b *responseBuilder) {
+		b.skip = true
+	}
+}
+
+// ResponseBuilder implements the AIP-158 pagination semantics.
+type ResponseBuilder interface {
+	// Resolve validates the request and returns the range of results to fetch.
+	// Callers should fetch up to Limit+1 results so that BuildPage can tell
+	// whether more results exist.
+	Resolve(req ListRequest) (PageRange, error)
+	// NextPageToken returns the token of the page following r, or an empty
+	// string when fetched does not exceed r.Limit. Encoding errors of the
+	// token generator are reported rather than ending the pagination.
+	NextPageToken(r PageRange, fetched int) (string, error)
+}
+
+// NewResponseBuilder creates a ResponseBuilder issuing tokens from gen.
+func NewResponseBuilder(gen TokenGenerator, opts ...ResponseOption) ResponseBuilder {
+	b := &responseBuilder{
+		gen:         gen,
+		defaultSize: 50,
+		maxSize:     1000,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+type responseBuilder struct {
+	gen         TokenGenerator
+	defaultSize int32
+	maxSize     int32
+	skip        bool
+}
+
+// Resolve validates the request and returns the range of results to fetch.
+func (b *responseBuilder) Resolve(req ListRequest) (PageRange, error) {
+	size := req.GetPageSize()
+	switch {
+	case size < 0:
+		return PageRange{}, ErrInvalidPageSize
+	case size == 0:
+		size = b.defaultSize
+	case size > b.maxSize:
+		size = b.maxSize
+	}
+	index, err := b.gen.GetIndex(req.GetPageToken())
+	if err != nil {
+		return PageRange{}, err
+	}
+	offset := int64(index)
+	if sr, ok := req.(SkipRequest); ok && b.skip {
+		if sr.GetSkip() < 0 {
+			return PageRange{}, ErrInvalidSkip
+		}
+		offset += int64(sr.GetSkip())
+	}
+	if offset < 0 || offset > math.MaxInt32 {
+		return PageRange{}, ErrInvalidToken
+	}
+	return PageRange{Offset: int32(offset), Limit: size}, nil
+}
+
+// NextPageToken returns the token of the page following r.
+func (b *responseBuilder) NextPageToken(r PageRange, fetched int) (string, error) {
+	if fetched <= int(r.Limit) {
+		return "", nil
+	}
+	return forIndexChecked(context.Background(), b.gen, int(r.Offset)+int(r.Limit))
+}
+
+// BuildPage creates the Page for items fetched with r, trimming the extra
+// lookahead item and emitting a next page token only when more results exist.
+func BuildPage[T any](b ResponseBuilder, r PageRange, items []T) (*Page[T], error) {
+	token, err := b.NextPageToken(r, len(items))
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[T]{
+		Items:         items,
+		NextPageToken: token,
+	}
+	if len(items) > int(r.Limit) {
+		page.Items = items[:r.Limit]
+	}
+	return page, nil
+}
@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResponseBuilderResolve(t *testing.T) {
+	gen := NewTokenGenerator()
+	b := NewResponseBuilder(gen, WithDefaultPageSize(10), WithMaxPageSize(50), WithSkipSupport())
+
+	tests := []struct {
+		req  testRequest
+		want PageRange
+	}{
+		{testRequest{}, PageRange{Offset: 0, Limit: 10}},
+		{testRequest{size: 100}, PageRange{Offset: 0, Limit: 50}},
+		{testRequest{size: 5, token: gen.ForIndex(20)}, PageRange{Offset: 20, Limit: 5}},
+		{testRequest{size: 5, skip: 3, token: gen.ForIndex(20)}, PageRange{Offset: 23, Limit: 5}},
+	}
+	for _, tt := range tests {
+		got, err := b.Resolve(tt.req)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected %+v, got %+v", tt.want, got)
+		}
+	}
+	if _, err := b.Resolve(testRequest{size: -1}); !errors.Is(err, ErrInvalidPageSize) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPageSize, err)
+	}
+	if _, err := b.Resolve(testRequest{skip: -1}); !errors.Is(err, ErrInvalidSkip) {
+		t.Fatalf("expected %v, got %v", ErrInvalidSkip, err)
+	}
+	if _, err := b.Resolve(testRequest{token: "!"}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+	if r, err := NewResponseBuilder(gen).Resolve(testRequest{skip: 3}); err != nil || r.Offset != 0 {
+		t.Fatalf("expected skip to be ignored without WithSkipSupport, got %+v, %v", r, err)
+	}
+}
+
+func TestBuildPage(t *testing.T) {
+	gen := NewTokenGenerator()
+	b := NewResponseBuilder(gen)
+	r := PageRange{Offset: 10, Limit: 2}
+
+	page, err := BuildPage(b, r, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !slices.Equal(page.Items, []int{1, 2}) {
+		t.Fatalf("expected the lookahead item to be trimmed, got %v", page.Items)
+	}
+	if index, err := gen.GetIndex(page.NextPageToken); err != nil || index != 12 {
+		t.Fatalf("expected %v, got %v, %v", 12, index, err)
+	}
+	page, err = BuildPage(b, r, []int{1, 2})
+	if err != nil || page.NextPageToken != "" || len(page.Items) != 2 {
+		t.Fatalf("expected the last page, got %+v, %v", page, err)
+	}
+}
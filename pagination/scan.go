@@ -0,0 +1,101 @@
+package pagination
+
+import (
+	"context"
+	"strconv"
+)
+
+// ScanTokenGenerator maps Redis SCAN, HSCAN, SSCAN and ZSCAN cursors to opaque
+// page tokens, so the raw cursors are not leaked to clients.
+// A zero cursor, which ends an iteration, maps to the empty token and back.
+type ScanTokenGenerator interface {
+	TokenGenerator
+	// ForScanCursor generates a page token for the given SCAN cursor.
+	ForScanCursor(uint64) string
+	// GetScanCursor retrieves the SCAN cursor from the given page token.
+	GetScanCursor(string) (uint64, error)
+}
+
+// ContextScanTokenGenerator is a ScanTokenGenerator whose tokens can depend
+// on the request context, as with WithTokenAudience.
+type ContextScanTokenGenerator interface {
+	ScanTokenGenerator
+	ContextTokenGenerator
+	// ForScanCursorContext generates a page token for the SCAN cursor.
+	ForScanCursorContext(context.Context, uint64) string
+	// GetScanCursorContext retrieves the SCAN cursor from the page token.
+	GetScanCursorContext(context.Context, string) (uint64, error)
+}
+
+// NewScanTokenGenerator creates a ScanTokenGenerator. The token options, such
+// as WithTokenSalt and WithHMACKey, are applied to the generated page tokens.
+// Like NewTokenGenerator, it panics if an option is invalid.
+func NewScanTokenGenerator(opts ...TokenOption) ContextScanTokenGenerator {
+	return &scanTokenGenerator{tokens: mustTokenGenerator(opts...)}
+}
+
+type scanTokenGenerator struct {
+	tokens *tokenGenerator
+}
+
+// ForIndex generates a page token for the given SCAN cursor.
+func (s *scanTokenGenerator) ForIndex(i int) string {
+	return s.ForScanCursor(uint64(i))
+}
+
+// GetIndex retrieves the SCAN cursor from the given page token.
+func (s *scanTokenGenerator) GetIndex(token string) (int, error) {
+	return s.GetIndexContext(context.Background(), token)
+}
+
+// ForIndexContext generates a page token for the given SCAN cursor.
+func (s *scanTokenGenerator) ForIndexContext(ctx context.Context, i int) string {
+	return s.ForScanCursorContext(ctx, uint64(i))
+}
+
+// GetIndexContext retrieves the SCAN cursor from the given page token.
+func (s *scanTokenGenerator) GetIndexContext(ctx context.Context, token string) (int, error) {
+	cursor, err := s.GetScanCursorContext(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return int(cursor), nil
+}
+
+// ForScanCursor generates a page token for the given SCAN cursor.
+func (s *scanTokenGenerator) ForScanCursor(cursor uint64) string {
+	return s.ForScanCursorContext(context.Background(), cursor)
+}
+
+// GetScanCursor retrieves the SCAN cursor from the given page token.
+func (s *scanTokenGenerator) GetScanCursor(token string) (uint64, error) {
+	return s.GetScanCursorContext(context.Background(), token)
+}
+
+// ForScanCursorContext generates a page token for the given SCAN cursor.
+func (s *scanTokenGenerator) ForScanCursorContext(ctx context.Context, cursor uint64) string {
+	if cursor == 0 {
+		return ""
+	}
+	token, err := s.tokens.encode(ctx, strconv.AppendUint(nil, cursor, 10))
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// GetScanCursorContext retrieves the SCAN cursor from the given page token.
+func (s *scanTokenGenerator) GetScanCursorContext(ctx context.Context, token string) (uint64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	payload, err := s.tokens.decode(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	cursor, err := strconv.ParseUint(string(payload), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return cursor, nil
+}
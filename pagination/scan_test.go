@@ -0,0 +1,27 @@
+package pagination
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestScanTokenGenerator(t *testing.T) {
+	gen := NewScanTokenGenerator(WithTokenSalt("salt"))
+
+	if token := gen.ForScanCursor(0); token != "" {
+		t.Fatalf("expected the finished scan to have no token, got %q", token)
+	}
+	if cursor, err := gen.GetScanCursor(""); err != nil || cursor != 0 {
+		t.Fatalf("expected the empty token to start the scan, got %v, %v", cursor, err)
+	}
+	if cursor, err := gen.GetScanCursor(gen.ForScanCursor(math.MaxUint64)); err != nil || cursor != math.MaxUint64 {
+		t.Fatalf("expected %v, got %v, %v", uint64(math.MaxUint64), cursor, err)
+	}
+	if index, err := gen.GetIndex(gen.ForIndex(42)); err != nil || index != 42 {
+		t.Fatalf("expected %v, got %v, %v", 42, index, err)
+	}
+	if _, err := gen.GetScanCursor(NewTokenGenerator(WithTokenSalt("salt")).ForIndex(-1)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
@@ -0,0 +1,53 @@
+package pagination
+
+import "context"
+
+// Slice returns the page of items selected by r, clamped at the slice bounds,
+// and the PageInfo describing it. A Limit of 0 selects all remaining items.
+func Slice[T any](items []T, r PageRange) ([]T, PageInfo) {
+	start := min(max(int(r.Offset), 0), len(items))
+	end := len(items)
+	if r.Limit > 0 {
+		end = min(start+int(r.Limit), len(items))
+	}
+	return items[start:end:end], PageInfo{
+		HasNextPage:     end < len(items),
+		HasPreviousPage: start > 0,
+		StartIndex:      start,
+		EndIndex:        end,
+	}
+}
+
+// SliceToken returns the page of size items following the page token,
+// with the PageInfo carrying the tokens of the adjacent pages.
+func SliceToken[T any](items []T, gen TokenGenerator, token string, size int) ([]T, PageInfo, error) {
+	if size <= 0 {
+		return nil, PageInfo{}, ErrInvalidPageSize
+	}
+	index, err := gen.GetIndex(token)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	if index < 0 {
+		return nil, PageInfo{}, ErrInvalidToken
+	}
+	start := min(index, len(items))
+	end := min(start+size, len(items))
+	info := PageInfo{
+		HasNextPage:     end < len(items),
+		HasPreviousPage: start > 0,
+		StartIndex:      start,
+		EndIndex:        end,
+	}
+	if info.HasNextPage {
+		if info.NextPageToken, err = forIndexChecked(context.Background(), gen, end); err != nil {
+			return nil, PageInfo{}, err
+		}
+	}
+	if info.HasPreviousPage {
+		if info.PreviousPageToken, err = forIndexChecked(context.Background(), gen, max(start-size, 0)); err != nil {
+			return nil, PageInfo{}, err
+		}
+	}
+	return items[start:end:end], info, nil
+}
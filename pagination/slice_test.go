@@ -0,0 +1,73 @@
+package pagination
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		r    PageRange
+		want []int
+		info PageInfo
+	}{
+		{PageRange{Offset: 0, Limit: 2}, []int{1, 2}, PageInfo{HasNextPage: true, EndIndex: 2}},
+		{PageRange{Offset: 2, Limit: 2}, []int{3, 4}, PageInfo{HasNextPage: true, HasPreviousPage: true, StartIndex: 2, EndIndex: 4}},
+		{PageRange{Offset: 4, Limit: 2}, []int{5}, PageInfo{HasPreviousPage: true, StartIndex: 4, EndIndex: 5}},
+		{PageRange{Offset: 9, Limit: 2}, []int{}, PageInfo{HasPreviousPage: true, StartIndex: 5, EndIndex: 5}},
+		{PageRange{Offset: 1}, []int{2, 3, 4, 5}, PageInfo{HasPreviousPage: true, StartIndex: 1, EndIndex: 5}},
+	}
+	for _, tt := range tests {
+		got, info := Slice(items, tt.r)
+		if !slices.Equal(got, tt.want) || info != tt.info {
+			t.Fatalf("expected %v, %+v, got %v, %+v", tt.want, tt.info, got, info)
+		}
+	}
+	page, _ := Slice(items, PageRange{Limit: 2})
+	if _ = append(page, 9); items[2] != 3 {
+		t.Fatalf("expected appending to a page not to modify the items, got %v", items)
+	}
+}
+
+func TestSliceToken(t *testing.T) {
+	gen := NewTokenGenerator()
+	items := []int{1, 2, 3, 4, 5}
+
+	var (
+		got   []int
+		token string
+	)
+	for {
+		page, info, err := SliceToken(items, gen, token, 2)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		got = append(got, page...)
+		if !info.HasNextPage {
+			if info.NextPageToken != "" {
+				t.Fatalf("expected no next token on the last page, got %q", info.NextPageToken)
+			}
+			break
+		}
+		token = info.NextPageToken
+	}
+	if !slices.Equal(got, items) {
+		t.Fatalf("expected %v, got %v", items, got)
+	}
+
+	_, info, err := SliceToken(items, gen, gen.ForIndex(3), 2)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if index, err := gen.GetIndex(info.PreviousPageToken); err != nil || index != 1 {
+		t.Fatalf("expected previous index %v, got %v, %v", 1, index, err)
+	}
+	if _, _, err := SliceToken(items, gen, "", 0); !errors.Is(err, ErrInvalidPageSize) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPageSize, err)
+	}
+	if _, _, err := SliceToken(items, gen, gen.ForIndex(-1), 2); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
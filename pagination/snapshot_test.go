@@ -0,0 +1,25 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSnapshotTokenGenerator(t *testing.T) {
+	gen := NewSnapshotTokenGenerator(WithHMACKey([]byte("secret")))
+
+	index, snapshot, err := gen.GetSnapshot(gen.ForSnapshot(20, "00000003-1"))
+	if err != nil || index != 20 || snapshot != "00000003-1" {
+		t.Fatalf("expected %v in %q, got %v in %q, %v", 20, "00000003-1", index, snapshot, err)
+	}
+	if index, snapshot, err := gen.GetSnapshot(""); err != nil || index != 0 || snapshot != "" {
+		t.Fatalf("expected the empty token to start without a snapshot, got %v, %q, %v", index, snapshot, err)
+	}
+	if index, err := gen.GetIndex(gen.ForIndex(5)); err != nil || index != 5 {
+		t.Fatalf("expected %v, got %v, %v", 5, index, err)
+	}
+	forged := NewSnapshotTokenGenerator().ForSnapshot(20, "other")
+	if _, _, err := gen.GetSnapshot(forged); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
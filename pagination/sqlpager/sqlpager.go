@@ -0,0 +1,124 @@
+// Package sqlpager builds parameterized SQL fragments for offset and keyset
+// pagination, usable with database/sql or sqlx. Fragments use "?" placeholders.
+package sqlpager
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+// ErrUnknownColumn is returned when a sort key has no column mapping.
+var ErrUnknownColumn = errors.New("unknown column")
+
+// Fragment is a parameterized SQL fragment.
+type Fragment struct {
+	SQL  string
+	Args []any
+}
+
+// Columns maps sort-key names to SQL column expressions.
+// A nil Columns uses the sort-key names as column names.
+type Columns map[string]string
+
+func (c Columns) column(name string) (string, error) {
+	if c == nil {
+		return name, nil
+	}
+	col, ok := c[name]
+	if !ok {
+		return "", ErrUnknownColumn
+	}
+	return col, nil
+}
+
+// Limit returns the "LIMIT ? OFFSET ?" fragment of the range. A zero limit
+// selects all rows: it yields an empty fragment, or a LIMIT of math.MaxInt64
+// when the range has an offset, since MySQL and SQLite reject a bare OFFSET.
+func Limit(r pagination.PageRange) Fragment {
+	if r.Limit == 0 {
+		if r.Offset == 0 {
+			return Fragment{}
+		}
+		return Fragment{SQL: "LIMIT ? OFFSET ?", Args: []any{int64(math.MaxInt64), r.Offset}}
+	}
+	return Fragment{
+		SQL:  "LIMIT ? OFFSET ?",
+		Args: []any{r.Limit, r.Offset},
+	}
+}
+
+// OrderBy returns the "ORDER BY" fragment of the sort keys.
+func OrderBy(keys []pagination.SortKey, cols Columns) (Fragment, error) {
+	exprs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		col, err := cols.column(key.Column)
+		if err != nil {
+			return Fragment{}, err
+		}
+		if key.Desc {
+			exprs = append(exprs, col+" DESC")
+		} else {
+			exprs = append(exprs, col+" ASC")
+		}
+	}
+	if len(exprs) == 0 {
+		return Fragment{}, nil
+	}
+	return Fragment{SQL: "ORDER BY " + strings.Join(exprs, ", ")}, nil
+}
+
+// Seek returns the WHERE condition selecting the rows after the cursor.
+// Keys sharing one direction use a row comparison such as
+// "(created_at, id) > (?, ?)"; mixed directions are expanded into OR terms.
+// The zero cursor yields an empty fragment.
+func Seek(keys []pagination.SortKey, c pagination.Cursor, cols Columns) (Fragment, error) {
+	if c.IsZero() {
+		return Fragment{}, nil
+	}
+	if len(c.Values) != len(keys) {
+		return Fragment{}, pagination.ErrInvalidToken
+	}
+	names := make([]string, 0, len(keys))
+	uniform := true
+	for _, key := range keys {
+		col, err := cols.column(key.Column)
+		if err != nil {
+			return Fragment{}, err
+		}
+		names = append(names, col)
+		uniform = uniform && key.Desc == keys[0].Desc
+	}
+	if uniform {
+		op := " > "
+		if keys[0].Desc {
+			op = " < "
+		}
+		marks := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		return Fragment{
+			SQL:  "(" + strings.Join(names, ", ") + ")" + op + "(" + marks + ")",
+			Args: append([]any(nil), c.Values...),
+		}, nil
+	}
+	var (
+		terms []string
+		args  []any
+	)
+	for i, key := range keys {
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, names[j]+" = ?")
+			args = append(args, c.Values[j])
+		}
+		if key.Desc {
+			conds = append(conds, names[i]+" < ?")
+		} else {
+			conds = append(conds, names[i]+" > ?")
+		}
+		args = append(args, c.Values[i])
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+	}
+	return Fragment{SQL: "(" + strings.Join(terms, " OR ") + ")", Args: args}, nil
+}
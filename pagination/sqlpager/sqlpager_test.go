@@ -0,0 +1,90 @@
+package sqlpager
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-kratos/kit/pagination"
+)
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		r    pagination.PageRange
+		want Fragment
+	}{
+		{pagination.PageRange{Offset: 20, Limit: 10}, Fragment{SQL: "LIMIT ? OFFSET ?", Args: []any{int32(10), int32(20)}}},
+		{pagination.PageRange{}, Fragment{}},
+		{pagination.PageRange{Offset: 5}, Fragment{SQL: "LIMIT ? OFFSET ?", Args: []any{int64(math.MaxInt64), int32(5)}}},
+	}
+	for _, tt := range tests {
+		if got := Limit(tt.r); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("expected %+v, got %+v", tt.want, got)
+		}
+	}
+}
+
+func TestLimitUnpaginated(t *testing.T) {
+	p := pagination.NewPaginator(1, 20, pagination.WithAllowUnpaginated())
+	if got := Limit(p.Resolve(1, -1)); got.SQL != "" {
+		t.Fatalf("expected no LIMIT clause, got %q", got.SQL)
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	keys := []pagination.SortKey{{Column: "created", Desc: true}, {Column: "id"}}
+	f, err := OrderBy(keys, Columns{"created": "created_at", "id": "id"})
+	if err != nil || f.SQL != "ORDER BY created_at DESC, id ASC" {
+		t.Fatalf("expected %q, got %q, %v", "ORDER BY created_at DESC, id ASC", f.SQL, err)
+	}
+	if _, err := OrderBy(keys, Columns{"id": "id"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Fatalf("expected %v, got %v", ErrUnknownColumn, err)
+	}
+	if f, err := OrderBy(nil, nil); err != nil || f.SQL != "" {
+		t.Fatalf("expected an empty fragment, got %q, %v", f.SQL, err)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	c := pagination.Cursor{Values: []any{"2024-01-01", 7}}
+	tests := []struct {
+		keys []pagination.SortKey
+		want Fragment
+	}{
+		{
+			[]pagination.SortKey{{Column: "created_at"}, {Column: "id"}},
+			Fragment{SQL: "(created_at, id) > (?, ?)", Args: []any{"2024-01-01", 7}},
+		},
+		{
+			[]pagination.SortKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}},
+			Fragment{SQL: "(created_at, id) < (?, ?)", Args: []any{"2024-01-01", 7}},
+		},
+		{
+			[]pagination.SortKey{{Column: "created_at", Desc: true}, {Column: "id"}},
+			Fragment{SQL: "((created_at < ?) OR (created_at = ? AND id > ?))", Args: []any{"2024-01-01", "2024-01-01", 7}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Seek(tt.keys, c, nil)
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("expected %+v, got %+v", tt.want, got)
+		}
+	}
+}
+
+func TestSeekErrors(t *testing.T) {
+	keys := []pagination.SortKey{{Column: "id"}}
+	if f, err := Seek(keys, pagination.Cursor{}, nil); err != nil || f.SQL != "" {
+		t.Fatalf("expected an empty fragment, got %q, %v", f.SQL, err)
+	}
+	if _, err := Seek(keys, pagination.Cursor{Values: []any{1, 2}}, nil); !errors.Is(err, pagination.ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", pagination.ErrInvalidToken, err)
+	}
+	if _, err := Seek(keys, pagination.Cursor{Values: []any{1}}, Columns{}); !errors.Is(err, ErrUnknownColumn) {
+		t.Fatalf("expected %v, got %v", ErrUnknownColumn, err)
+	}
+}
@@ -0,0 +1,104 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FieldViolation describes an invalid field of a request, suitable for
+// mapping to a BadRequest field violation.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError is returned by ParseStrict with the violations of all
+// invalid request fields.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Field+": "+v.Description)
+	}
+	return "invalid pagination request: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// ParseStrict parses the request with p.ParseStrict when p implements
+// StrictPaginator, and with p.Parse otherwise.
+func ParseStrict(p Paginator, req PageRequest) (PageRange, error) {
+	if sp, ok := p.(StrictPaginator); ok {
+		return sp.ParseStrict(req)
+	}
+	return p.Parse(req), nil
+}
+
+// ParseStrict validates the request and resolves it, reporting negative
+// pages, sizes and skips, sizes above the maximum and malformed page tokens
+// in a *ValidationError instead of normalizing them.
+func (p *paginator) ParseStrict(req PageRequest) (PageRange, error) {
+	var (
+		verr ValidationError
+		page = req.GetPageNum()
+		size = req.GetPageSize()
+		skip int32
+	)
+	if page < 0 {
+		verr.add("page_num", "must not be negative")
+	}
+	switch {
+	case p.AllowAll && size == -1:
+	case size < 0:
+		verr.add("page_size", "must not be negative")
+	case size == 0 && p.ZeroSize == ZeroSizeReject:
+		verr.add("page_size", "must be specified")
+	case p.MaxSize > 0 && size > p.MaxSize:
+		verr.add("page_size", "must not exceed %d", p.MaxSize)
+	}
+	if sr, ok := req.(SkipRequest); ok {
+		if skip = sr.GetSkip(); skip < 0 {
+			verr.add("skip", "must not be negative")
+		}
+	}
+	index := -1
+	if tr, ok := req.(TokenRequest); ok && p.Tokens != nil && tr.GetPageToken() != "" {
+		i, err := p.Tokens.GetIndex(tr.GetPageToken())
+		switch {
+		case errors.Is(err, ErrTokenExpired):
+			verr.add("page_token", "has expired")
+		case err != nil || i < 0:
+			verr.add("page_token", "is malformed")
+		default:
+			index = i
+		}
+	}
+	if len(verr.Violations) > 0 {
+		return PageRange{}, &verr
+	}
+	if size == 0 && p.ZeroSize == ZeroSizeEmpty {
+		return PageRange{}, ErrEmptyPage
+	}
+	r, err := resolveRange(p, int64(page), int64(size))
+	if err != nil {
+		verr.add("page_num", "offset overflows")
+		return PageRange{}, &verr
+	}
+	if index >= 0 {
+		r.Offset = int64(index)
+	}
+	r.Offset += int64(skip)
+	if r.Offset > math.MaxInt32 {
+		verr.add("page_num", "offset overflows")
+		return PageRange{}, &verr
+	}
+	return PageRange{Offset: int32(r.Offset), Limit: int32(r.Limit)}, nil
+}
@@ -0,0 +1,103 @@
+package pagination
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// TimeSeriesID is the constraint for the tiebreaker ids of time series rows.
+type TimeSeriesID interface {
+	~int64 | ~string
+}
+
+// TimeCursor is the position of a row in (timestamp, id) order.
+type TimeCursor[ID TimeSeriesID] struct {
+	Time time.Time `json:"t"`
+	ID   ID        `json:"id"`
+}
+
+// TimeRange bounds time series queries to [Start, End). A zero bound is open.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// TimeSeries builds seek queries over rows ordered by a timestamp column,
+// using an id column to break ties between equal timestamps.
+type TimeSeries[ID TimeSeriesID] struct {
+	timeColumn string
+	idColumn   string
+	desc       bool
+	codec      ContextTokenCodec[TimeCursor[ID]]
+}
+
+// NewTimeSeries creates a TimeSeries over the given columns. With desc set,
+// pages scroll back in time from the newest rows. The token options are
+// applied to the generated page tokens.
+func NewTimeSeries[ID TimeSeriesID](timeColumn, idColumn string, desc bool, opts ...TokenOption) *TimeSeries[ID] {
+	return &TimeSeries[ID]{
+		timeColumn: timeColumn,
+		idColumn:   idColumn,
+		desc:       desc,
+		codec:      NewTokenCodec[TimeCursor[ID]](opts...),
+	}
+}
+
+// NextPageToken returns the page token following the row at c.
+func (ts *TimeSeries[ID]) NextPageToken(c TimeCursor[ID]) (string, error) {
+	return ts.NextPageTokenContext(context.Background(), c)
+}
+
+// NextPageTokenContext returns the page token following the row at c for
+// the context.
+func (ts *TimeSeries[ID]) NextPageTokenContext(ctx context.Context, c TimeCursor[ID]) (string, error) {
+	return ts.codec.EncodeContext(ctx, c)
+}
+
+// Where returns the predicate selecting the rows after the page token,
+// clamped to the time range. The empty token starts at the range bound.
+func (ts *TimeSeries[ID]) Where(token string, r TimeRange) (Predicate, error) {
+	return ts.WhereContext(context.Background(), token, r)
+}
+
+// WhereContext is like Where, decoding the page token for the context.
+func (ts *TimeSeries[ID]) WhereContext(ctx context.Context, token string, r TimeRange) (Predicate, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if !r.Start.IsZero() {
+		conds = append(conds, ts.timeColumn+" >= ?")
+		args = append(args, r.Start)
+	}
+	if !r.End.IsZero() {
+		conds = append(conds, ts.timeColumn+" < ?")
+		args = append(args, r.End)
+	}
+	if token != "" {
+		c, err := ts.codec.DecodeContext(ctx, token)
+		if err != nil {
+			return Predicate{}, err
+		}
+		op := " > ?"
+		if ts.desc {
+			op = " < ?"
+		}
+		conds = append(conds, "("+ts.timeColumn+op+" OR ("+ts.timeColumn+" = ? AND "+ts.idColumn+op+"))")
+		args = append(args, c.Time, c.Time, c.ID)
+	}
+	if len(conds) == 0 {
+		return Predicate{}, nil
+	}
+	return Predicate{SQL: strings.Join(conds, " AND "), Args: args}, nil
+}
+
+// OrderBy renders the ORDER BY expression of the time series.
+func (ts *TimeSeries[ID]) OrderBy() string {
+	dir := " ASC"
+	if ts.desc {
+		dir = " DESC"
+	}
+	return ts.timeColumn + dir + ", " + ts.idColumn + dir
+}
@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeSeries(t *testing.T) {
+	ts := NewTimeSeries[int64]("created_at", "id", true)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	start, end := at.Add(-time.Hour), at.Add(time.Hour)
+
+	if got := ts.OrderBy(); got != "created_at DESC, id DESC" {
+		t.Fatalf("expected %q, got %q", "created_at DESC, id DESC", got)
+	}
+	p, err := ts.Where("", TimeRange{})
+	if err != nil || p.SQL != "" {
+		t.Fatalf("expected an empty predicate, got %+v, %v", p, err)
+	}
+	token, err := ts.NextPageToken(TimeCursor[int64]{Time: at, ID: 7})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	p, err = ts.Where(token, TimeRange{Start: start, End: end})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	want := Predicate{
+		SQL:  "created_at >= ? AND created_at < ? AND (created_at < ? OR (created_at = ? AND id < ?))",
+		Args: []any{start, end, at, at, int64(7)},
+	}
+	if p.SQL != want.SQL || len(p.Args) != len(want.Args) {
+		t.Fatalf("expected %+v, got %+v", want, p)
+	}
+	for i, arg := range want.Args {
+		if tm, ok := arg.(time.Time); ok {
+			if !tm.Equal(p.Args[i].(time.Time)) {
+				t.Fatalf("expected %v, got %v", tm, p.Args[i])
+			}
+		} else if !reflect.DeepEqual(arg, p.Args[i]) {
+			t.Fatalf("expected %v, got %v", arg, p.Args[i])
+		}
+	}
+	if _, err := ts.Where("!", TimeRange{}); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTimeSeriesAscending(t *testing.T) {
+	ts := NewTimeSeries[string]("at", "uuid", false)
+	token, err := ts.NextPageToken(TimeCursor[string]{Time: time.Unix(0, 0), ID: "a"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	p, err := ts.Where(token, TimeRange{})
+	if err != nil || p.SQL != "(at > ? OR (at = ? AND uuid > ?))" || p.Args[2] != "a" {
+		t.Fatalf("unexpected predicate %+v, %v", p, err)
+	}
+	if got := ts.OrderBy(); got != "at ASC, uuid ASC" {
+		t.Fatalf("expected %q, got %q", "at ASC, uuid ASC", got)
+	}
+}
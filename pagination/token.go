@@ -1,16 +1,42 @@
 package pagination
 
 import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
+	"time"
 )
 
 // ErrInvalidToken is returned when a page token is invalid.
 var ErrInvalidToken = errors.New("invalid page token")
 
+// ErrTokenExpired is returned when a page token is older than its TTL.
+var ErrTokenExpired = errors.New("page token expired")
+
+// ErrTokenMismatch is returned when a page token was generated for different
+// request fields than the ones it is used with.
+var ErrTokenMismatch = errors.New("page token does not match request")
+
+// bindingSize is the length of the request fields checksum in tokens.
+const bindingSize = 8
+
+// defaultMaxTokenLength is the default limit of the length of decoded tokens.
+const defaultMaxTokenLength = 4096
+
+// versionMarker precedes the format version in versioned tokens. It cannot
+// start a timestamp or a JSON or decimal payload, so legacy tokens are only
+// mistaken for versioned ones if their checksums start with it.
+const versionMarker = "\x00v"
+
 // TokenOption defines options for the TokenGenerator.
 type TokenOption func(*tokenGenerator)
 
@@ -21,9 +47,200 @@ func WithTokenSalt(salt string) TokenOption {
 	}
 }
 
-// NewTokenGenerator provides a new instance of a TokenGenerator.
+// WithTokenSalts sets the salt for the token generation, while tokens generated
+// with any of the previous salts are still accepted during a rotation window.
+func WithTokenSalts(primary string, previous ...string) TokenOption {
+	return func(t *tokenGenerator) {
+		t.salt = primary
+		t.oldSalts = previous
+	}
+}
+
+// WithHMACKey signs tokens with HMAC-SHA256 using the given key,
+// so tampered tokens are rejected with ErrInvalidToken.
+func WithHMACKey(key []byte) TokenOption {
+	return func(t *tokenGenerator) {
+		t.hmacKey = key
+	}
+}
+
+// WithURLSafeEncoding encodes tokens with unpadded base64url, so they can be
+// put into query strings unescaped. Tokens in either encoding are accepted.
+func WithURLSafeEncoding() TokenOption {
+	return func(t *tokenGenerator) {
+		t.codec = NewBase64Codec(base64.RawURLEncoding)
+	}
+}
+
+// WithMaxTokenLength limits the length of the tokens that are decoded;
+// longer tokens are rejected with ErrInvalidToken before any processing.
+// The default limit is 4096 bytes.
+func WithMaxTokenLength(n int) TokenOption {
+	return func(t *tokenGenerator) {
+		if n > 0 {
+			t.maxLength = n
+		}
+	}
+}
+
+// WithTokenCodec replaces the base64 codec turning the protected token bytes
+// into strings, e.g. to compress tokens or to store them externally. Decode
+// errors are returned unchanged, so codecs should report malformed tokens with
+// ErrInvalidToken. Since ForIndex cannot report errors, it returns an empty
+// token when the codec fails to encode; ForIndexChecked reports the error.
+func WithTokenCodec(c TokenCodec[[]byte]) TokenOption {
+	return func(t *tokenGenerator) {
+		t.codec = c
+	}
+}
+
+// NewBase64Codec creates the default TokenCodec encoding token bytes with enc.
+// Decoding also accepts the standard and unpadded base64url encodings, so the
+// encoding can be changed without invalidating tokens held by clients, but
+// rejects non-canonical encodings, such as ones with non-zero padding bits or
+// embedded newlines.
+func NewBase64Codec(enc *base64.Encoding) TokenCodec[[]byte] {
+	return base64Codec{enc: enc}
+}
+
+type base64Codec struct {
+	enc *base64.Encoding
+}
+
+// Encode encodes the bytes into a token.
+func (c base64Codec) Encode(bs []byte) (string, error) {
+	return c.enc.EncodeToString(bs), nil
+}
+
+// Decode decodes the bytes from a token.
+func (c base64Codec) Decode(token string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{c.enc, base64.StdEncoding, base64.RawURLEncoding} {
+		bs, err := enc.Strict().DecodeString(token)
+		if err == nil && enc.EncodedLen(len(bs)) == len(token) {
+			return bs, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// TokenDecoder migrates the payload of a token generated with an older
+// format version into the payload format of the current version.
+type TokenDecoder func(payload []byte) ([]byte, error)
+
+// WithTokenVersion prefixes token payloads with a marker and the given
+// format version, which must not be 0. Tokens of other versions are migrated with
+// the decoder registered by WithTokenDecoder for their version, or rejected
+// with ErrInvalidToken when there is none. Tokens generated before versioning
+// was enabled are treated as version 0 and accepted as is unless a decoder is
+// registered for version 0.
+func WithTokenVersion(version byte) TokenOption {
+	return func(t *tokenGenerator) {
+		if version > 0 {
+			t.version = version
+		}
+	}
+}
+
+// WithTokenDecoder registers the decoder migrating payloads of tokens with
+// the given format version.
+func WithTokenDecoder(version byte, dec TokenDecoder) TokenOption {
+	return func(t *tokenGenerator) {
+		if t.decoders == nil {
+			t.decoders = make(map[byte]TokenDecoder)
+		}
+		t.decoders[version] = dec
+	}
+}
+
+// WithTokenBinding embeds a checksum of the given request fields, such as the
+// filter and order_by of a list request, into tokens. Tokens used with other
+// field values are rejected with ErrTokenMismatch, as required by AIP-158.
+func WithTokenBinding(fields ...string) TokenOption {
+	return func(t *tokenGenerator) {
+		t.binding = checksum(fields...)
+	}
+}
+
+// WithTokenAudience embeds a checksum of the caller identity returned by
+// audience, such as a user or tenant id, into tokens. Tokens used by another
+// caller are rejected with ErrTokenMismatch, preventing replay across users.
+// The identity is read from the context passed to the Context methods, such
+// as ForIndexContext, EncodeContext and ForCursorContext; the methods without
+// a context use context.Background.
+func WithTokenAudience(audience func(ctx context.Context) string) TokenOption {
+	return func(t *tokenGenerator) {
+		t.audience = audience
+	}
+}
+
+// WithTokenTTL embeds the issue time into tokens, which are then
+// rejected with ErrTokenExpired once the ttl has passed.
+func WithTokenTTL(ttl time.Duration) TokenOption {
+	return func(t *tokenGenerator) {
+		if ttl > 0 {
+			t.ttl = ttl
+		}
+	}
+}
+
+// WithTokenEncryption encrypts tokens with AES-GCM using key, which must be
+// 16, 24 or 32 bytes long. Tokens encrypted with any of the previous keys are
+// still accepted, which allows keys to be rotated without breaking clients.
+// An invalid key makes NewTokenGenerator panic and is reported as an error
+// by NewTokenGeneratorChecked.
+func WithTokenEncryption(key []byte, previous ...[]byte) TokenOption {
+	return func(t *tokenGenerator) {
+		t.aeads = t.aeads[:0]
+		for _, k := range append([][]byte{key}, previous...) {
+			aead, err := newAEAD(k)
+			if err != nil {
+				t.err = err
+				return
+			}
+			t.aeads = append(t.aeads, aead)
+		}
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("pagination: invalid token encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewTokenGenerator provides a new instance of a TokenGenerator. It panics if
+// an option is invalid, such as an encryption key of the wrong length; use
+// NewTokenGeneratorChecked to handle the error instead.
 func NewTokenGenerator(opts ...TokenOption) TokenGenerator {
-	t := &tokenGenerator{}
+	return mustTokenGenerator(opts...)
+}
+
+// NewTokenGeneratorChecked is like NewTokenGenerator, but reports invalid
+// options with an error.
+func NewTokenGeneratorChecked(opts ...TokenOption) (TokenGenerator, error) {
+	t := newTokenGenerator(opts...)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return t, nil
+}
+
+func mustTokenGenerator(opts ...TokenOption) *tokenGenerator {
+	t := newTokenGenerator(opts...)
+	if t.err != nil {
+		panic(t.err)
+	}
+	return t
+}
+
+func newTokenGenerator(opts ...TokenOption) *tokenGenerator {
+	t := &tokenGenerator{
+		codec:     NewBase64Codec(base64.StdEncoding),
+		maxLength: defaultMaxTokenLength,
+		now:       time.Now,
+	}
 	for _, opt := range opts {
 		opt(t)
 	}
@@ -42,8 +259,47 @@ type TokenGenerator interface {
 	GetIndex(string) (int, error)
 }
 
+// ContextTokenGenerator is a TokenGenerator whose tokens can depend on the
+// request context, as with WithTokenAudience.
+type ContextTokenGenerator interface {
+	TokenGenerator
+	ForIndexContext(context.Context, int) string
+	GetIndexContext(context.Context, string) (int, error)
+}
+
+// CheckedTokenGenerator is a ContextTokenGenerator reporting encoding errors,
+// such as a failing WithTokenCodec, instead of returning an empty token that
+// reads as the last page. The generators created by NewTokenGenerator
+// implement it.
+type CheckedTokenGenerator interface {
+	ContextTokenGenerator
+	// ForIndexChecked is like ForIndexContext, but reports encoding errors.
+	ForIndexChecked(context.Context, int) (string, error)
+}
+
+// forIndexChecked generates the page token of gen for the given index,
+// reporting encoding errors when gen implements CheckedTokenGenerator.
+func forIndexChecked(ctx context.Context, gen TokenGenerator, i int) (string, error) {
+	if cg, ok := gen.(CheckedTokenGenerator); ok {
+		return cg.ForIndexChecked(ctx, i)
+	}
+	return gen.ForIndex(i), nil
+}
+
 type tokenGenerator struct {
-	salt string
+	salt      string
+	oldSalts  []string
+	hmacKey   []byte
+	ttl       time.Duration
+	aeads     []cipher.AEAD
+	codec     TokenCodec[[]byte]
+	version   byte
+	decoders  map[byte]TokenDecoder
+	binding   []byte
+	audience  func(context.Context) string
+	maxLength int
+	now       func() time.Time
+	err       error
 }
 
 // Parse extracts the index from the page token in the request.
@@ -54,24 +310,207 @@ func (t *tokenGenerator) Parse(req TokenRequest) (int, error) {
 
 // ForIndex generates a page token for the given index.
 func (t *tokenGenerator) ForIndex(i int) string {
-	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s%d", t.salt, i)))
+	return t.ForIndexContext(context.Background(), i)
 }
 
 // GetIndex retrieves the index from the given page token.
 func (t *tokenGenerator) GetIndex(token string) (int, error) {
+	return t.GetIndexContext(context.Background(), token)
+}
+
+// ForIndexContext generates a page token for the given index.
+func (t *tokenGenerator) ForIndexContext(ctx context.Context, i int) string {
+	token, err := t.ForIndexChecked(ctx, i)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// ForIndexChecked generates a page token for the given index, reporting
+// encoding errors.
+func (t *tokenGenerator) ForIndexChecked(ctx context.Context, i int) (string, error) {
+	return t.encode(ctx, []byte(strconv.Itoa(i)))
+}
+
+// GetIndexContext retrieves the index from the given page token.
+func (t *tokenGenerator) GetIndexContext(ctx context.Context, token string) (int, error) {
 	if token == "" {
 		return 0, nil
 	}
-	bs, err := base64.StdEncoding.DecodeString(token)
+	payload, err := t.decode(ctx, token)
 	if err != nil {
-		return 0, ErrInvalidToken
-	}
-	if !strings.HasPrefix(string(bs), t.salt) {
-		return 0, ErrInvalidToken
+		return 0, err
 	}
-	index, err := strconv.Atoi(strings.TrimPrefix(string(bs), t.salt))
+	index, err := strconv.Atoi(string(payload))
 	if err != nil {
 		return 0, ErrInvalidToken
 	}
 	return index, nil
 }
+
+// encode wraps the payload with the salt, the optional format version, the
+// optional issue time, the optional request and caller bindings and the
+// optional signature, then encrypts it when encryption is enabled.
+func (t *tokenGenerator) encode(ctx context.Context, payload []byte) (string, error) {
+	if t.err != nil {
+		return "", t.err
+	}
+	bs := make([]byte, 0, len(t.salt)+len(payload)+sha256.Size+2*bindingSize+22)
+	bs = append(bs, t.salt...)
+	if t.version > 0 {
+		bs = append(bs, versionMarker...)
+		bs = append(bs, t.version)
+	}
+	if t.ttl > 0 {
+		bs = strconv.AppendInt(bs, t.now().Unix(), 10)
+		bs = append(bs, '.')
+	}
+	bs = append(bs, t.binding...)
+	if t.audience != nil {
+		bs = append(bs, checksum(t.audience(ctx))...)
+	}
+	bs = append(bs, payload...)
+	if len(t.hmacKey) > 0 {
+		bs = append(bs, t.sign(bs)...)
+	}
+	if len(t.aeads) > 0 {
+		bs = t.seal(bs)
+	}
+	return t.codec.Encode(bs)
+}
+
+// decode verifies the token and returns the payload it carries.
+func (t *tokenGenerator) decode(ctx context.Context, token string) ([]byte, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if len(token) > t.maxLength {
+		return nil, ErrInvalidToken
+	}
+	bs, err := t.codec.Decode(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.aeads) > 0 {
+		if bs, err = t.open(bs); err != nil {
+			return nil, err
+		}
+	}
+	if len(t.hmacKey) > 0 {
+		if len(bs) < sha256.Size {
+			return nil, ErrInvalidToken
+		}
+		mac := bs[len(bs)-sha256.Size:]
+		bs = bs[:len(bs)-sha256.Size]
+		if !hmac.Equal(mac, t.sign(bs)) {
+			return nil, ErrInvalidToken
+		}
+	}
+	bs, ok := t.trimSalt(bs)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	var version byte
+	if t.version > 0 && len(bs) > len(versionMarker) && string(bs[:len(versionMarker)]) == versionMarker {
+		version, bs = bs[len(versionMarker)], bs[len(versionMarker)+1:]
+	}
+	if t.ttl > 0 {
+		i := bytes.IndexByte(bs, '.')
+		if i < 0 {
+			return nil, ErrInvalidToken
+		}
+		issued, err := strconv.ParseInt(string(bs[:i]), 10, 64)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		if t.now().Sub(time.Unix(issued, 0)) > t.ttl {
+			return nil, ErrTokenExpired
+		}
+		bs = bs[i+1:]
+	}
+	if len(t.binding) > 0 {
+		if len(bs) < bindingSize || !hmac.Equal(bs[:bindingSize], t.binding) {
+			return nil, ErrTokenMismatch
+		}
+		bs = bs[bindingSize:]
+	}
+	if t.audience != nil {
+		if len(bs) < bindingSize || !hmac.Equal(bs[:bindingSize], checksum(t.audience(ctx))) {
+			return nil, ErrTokenMismatch
+		}
+		bs = bs[bindingSize:]
+	}
+	if t.version > 0 && version != t.version {
+		dec, ok := t.decoders[version]
+		if !ok {
+			if version == 0 {
+				return bs, nil
+			}
+			return nil, ErrInvalidToken
+		}
+		if bs, err = dec(bs); err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+	return bs, nil
+}
+
+// checksum returns a short digest of the fields.
+func checksum(fields ...string) []byte {
+	h := sha256.New()
+	for _, f := range fields {
+		h.Write(strconv.AppendInt(nil, int64(len(f)), 10))
+		h.Write([]byte{':'})
+		h.Write([]byte(f))
+	}
+	return h.Sum(nil)[:bindingSize]
+}
+
+// trimSalt removes the primary or a previous salt from bs.
+// The salts are compared in constant time.
+func (t *tokenGenerator) trimSalt(bs []byte) ([]byte, bool) {
+	if hasPrefix(bs, t.salt) {
+		return bs[len(t.salt):], true
+	}
+	for _, salt := range t.oldSalts {
+		if hasPrefix(bs, salt) {
+			return bs[len(salt):], true
+		}
+	}
+	return nil, false
+}
+
+func hasPrefix(bs []byte, prefix string) bool {
+	return len(bs) >= len(prefix) && subtle.ConstantTimeCompare(bs[:len(prefix)], []byte(prefix)) == 1
+}
+
+func (t *tokenGenerator) sign(bs []byte) []byte {
+	h := hmac.New(sha256.New, t.hmacKey)
+	h.Write(bs)
+	return h.Sum(nil)
+}
+
+// seal encrypts bs with the primary key, prefixing the random nonce.
+func (t *tokenGenerator) seal(bs []byte) []byte {
+	aead := t.aeads[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(bs)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		panic("pagination: failed to generate nonce: " + err.Error())
+	}
+	return aead.Seal(nonce, nonce, bs, nil)
+}
+
+// open decrypts bs with the first key that authenticates it.
+func (t *tokenGenerator) open(bs []byte) ([]byte, error) {
+	for _, aead := range t.aeads {
+		if len(bs) < aead.NonceSize() {
+			continue
+		}
+		nonce, ciphertext := bs[:aead.NonceSize()], bs[aead.NonceSize():]
+		if plain, err := aead.Open(nil, nonce, ciphertext, nil); err == nil {
+			return plain, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
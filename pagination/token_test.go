@@ -0,0 +1,296 @@
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	gen := NewTokenGenerator(WithTokenSalt("salt"))
+
+	index, err := gen.GetIndex(gen.ForIndex(42))
+	if err != nil {
+		t.Fatalf("GetIndex returned unexpected error: %v", err)
+	}
+	if index != 42 {
+		t.Fatalf("expected index 42, got %d", index)
+	}
+}
+
+func TestTokenHMACRejectsTampering(t *testing.T) {
+	gen := NewTokenGenerator(WithHMACKey([]byte("secret")))
+
+	token := gen.ForIndex(10)
+	if index, err := gen.GetIndex(token); err != nil || index != 10 {
+		t.Fatalf("expected index 10, got %d (%v)", index, err)
+	}
+
+	bs, _ := base64.StdEncoding.DecodeString(token)
+	bs[0] = '9'
+	forged := base64.StdEncoding.EncodeToString(bs)
+	if _, err := gen.GetIndex(forged); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+
+	other := NewTokenGenerator(WithHMACKey([]byte("other")))
+	if _, err := other.GetIndex(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTokenTTLExpires(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	gen := newTokenGenerator(WithTokenTTL(time.Minute))
+	gen.now = func() time.Time { return now }
+
+	token := gen.ForIndex(5)
+	if index, err := gen.GetIndex(token); err != nil || index != 5 {
+		t.Fatalf("expected index 5, got %d (%v)", index, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := gen.GetIndex(token); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("expected %v, got %v", ErrTokenExpired, err)
+	}
+}
+
+func TestTokenEncryptionKeyRotation(t *testing.T) {
+	oldKey := []byte("0123456789abcdef")
+	newKey := []byte("fedcba9876543210")
+
+	token := NewTokenGenerator(WithTokenEncryption(oldKey)).ForIndex(7)
+	gen := NewTokenGenerator(WithTokenEncryption(newKey, oldKey))
+	if index, err := gen.GetIndex(token); err != nil || index != 7 {
+		t.Fatalf("expected index 7, got %d (%v)", index, err)
+	}
+
+	rotated := NewTokenGenerator(WithTokenEncryption(newKey))
+	if _, err := rotated.GetIndex(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTokenURLSafeEncodingAcceptsBoth(t *testing.T) {
+	std := NewTokenGenerator(WithHMACKey([]byte("k")))
+	url := NewTokenGenerator(WithHMACKey([]byte("k")), WithURLSafeEncoding())
+
+	for _, token := range []string{std.ForIndex(99), url.ForIndex(99)} {
+		if index, err := url.GetIndex(token); err != nil || index != 99 {
+			t.Fatalf("GetIndex(%q) = %d, %v", token, index, err)
+		}
+	}
+	if token := url.ForIndex(99); strings.ContainsAny(token, "+/=") {
+		t.Fatalf("expected url-safe token, got %q", token)
+	}
+}
+
+func TestTokenVersionMigration(t *testing.T) {
+	v1 := NewTokenGenerator(WithTokenVersion(1))
+	token := v1.ForIndex(3)
+
+	v2 := NewTokenGenerator(
+		WithTokenVersion(2),
+		WithTokenDecoder(1, func(payload []byte) ([]byte, error) {
+			return append(payload, '0'), nil
+		}),
+	)
+	if index, err := v2.GetIndex(token); err != nil || index != 30 {
+		t.Fatalf("expected migrated index 30, got %d (%v)", index, err)
+	}
+	if index, err := v2.GetIndex(NewTokenGenerator().ForIndex(4)); err != nil || index != 4 {
+		t.Fatalf("expected legacy index 4, got %d (%v)", index, err)
+	}
+	if _, err := NewTokenGenerator(WithTokenVersion(3)).GetIndex(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTokenVersionLegacyChecksum(t *testing.T) {
+	// A legacy binding checksum starting with a byte below 32 was once
+	// mistaken for a format version.
+	field := ""
+	for i := 0; field == ""; i++ {
+		if f := strconv.Itoa(i); checksum(f)[0] < 32 {
+			field = f
+		}
+	}
+	token := NewTokenGenerator(WithTokenBinding(field)).ForIndex(4)
+	gen := NewTokenGenerator(WithTokenVersion(2), WithTokenBinding(field))
+	if index, err := gen.GetIndex(token); err != nil || index != 4 {
+		t.Fatalf("expected legacy index 4, got %d (%v)", index, err)
+	}
+	if index, err := gen.GetIndex(gen.ForIndex(5)); err != nil || index != 5 {
+		t.Fatalf("expected index 5, got %d (%v)", index, err)
+	}
+}
+
+func TestTokenEncryptionInvalidKey(t *testing.T) {
+	if _, err := NewTokenGeneratorChecked(WithTokenEncryption([]byte("short"))); err == nil {
+		t.Fatal("expected error for an invalid key, got nil")
+	}
+	if _, err := NewTokenGeneratorChecked(WithTokenEncryption(make([]byte, 32), []byte("short"))); err == nil {
+		t.Fatal("expected error for an invalid previous key, got nil")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected NewTokenGenerator to panic on an invalid key")
+			}
+		}()
+		NewTokenGenerator(WithTokenEncryption([]byte("short")))
+	}()
+	if _, err := NewTokenGeneratorChecked(WithTokenEncryption(make([]byte, 16))); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestTokenBindingMismatch(t *testing.T) {
+	token := NewTokenGenerator(WithTokenBinding(`name = "a"`, "id")).ForIndex(8)
+
+	if index, err := NewTokenGenerator(WithTokenBinding(`name = "a"`, "id")).GetIndex(token); err != nil || index != 8 {
+		t.Fatalf("expected index 8, got %d (%v)", index, err)
+	}
+	if _, err := NewTokenGenerator(WithTokenBinding(`name = "b"`, "id")).GetIndex(token); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("expected %v, got %v", ErrTokenMismatch, err)
+	}
+}
+
+func TestTokenAudience(t *testing.T) {
+	type userKey struct{}
+	gen := NewTokenGenerator(WithTokenAudience(func(ctx context.Context) string {
+		user, _ := ctx.Value(userKey{}).(string)
+		return user
+	})).(ContextTokenGenerator)
+	alice := context.WithValue(context.Background(), userKey{}, "alice")
+	bob := context.WithValue(context.Background(), userKey{}, "bob")
+
+	token := gen.ForIndexContext(alice, 12)
+	if index, err := gen.GetIndexContext(alice, token); err != nil || index != 12 {
+		t.Fatalf("expected index 12, got %d (%v)", index, err)
+	}
+	if _, err := gen.GetIndexContext(bob, token); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("expected %v, got %v", ErrTokenMismatch, err)
+	}
+}
+
+func TestTokenAudienceDerived(t *testing.T) {
+	type userKey struct{}
+	audience := WithTokenAudience(func(ctx context.Context) string {
+		user, _ := ctx.Value(userKey{}).(string)
+		return user
+	})
+	alice := context.WithValue(context.Background(), userKey{}, "alice")
+	bob := context.WithValue(context.Background(), userKey{}, "bob")
+
+	codec := NewTokenCodec[int](audience)
+	token, err := codec.EncodeContext(alice, 3)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err := codec.DecodeContext(bob, token); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("expected %v, got %v", ErrTokenMismatch, err)
+	}
+
+	scan := NewScanTokenGenerator(audience)
+	token = scan.ForScanCursorContext(alice, 42)
+	if cursor, err := scan.GetScanCursorContext(alice, token); err != nil || cursor != 42 {
+		t.Fatalf("expected cursor 42, got %d (%v)", cursor, err)
+	}
+	if _, err := scan.GetScanCursorContext(bob, token); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("expected %v, got %v", ErrTokenMismatch, err)
+	}
+
+	snapshot := NewSnapshotTokenGenerator(audience)
+	token = snapshot.ForSnapshotContext(alice, 5, "ts")
+	if _, _, err := snapshot.GetSnapshotContext(bob, token); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("expected %v, got %v", ErrTokenMismatch, err)
+	}
+
+	cursors := NewCursorPaginator([]SortKey{{Column: "id"}}, audience)
+	token, err = cursors.ForCursorContext(alice, Cursor{Values: []any{7}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err := cursors.GetCursorContext(bob, token); !errors.Is(err, ErrTokenMismatch) {
+		t.Fatalf("expected %v, got %v", ErrTokenMismatch, err)
+	}
+}
+
+func TestTokenRejectsMalformed(t *testing.T) {
+	gen := NewTokenGenerator(WithTokenSalt("salt"), WithMaxTokenLength(64))
+	token := gen.ForIndex(7)
+
+	for _, bad := range []string{
+		strings.Repeat("A", 65),
+		token[:4] + "\n" + token[4:],
+		token + "=",
+	} {
+		if _, err := gen.GetIndex(bad); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected %v for %q, got %v", ErrInvalidToken, bad, err)
+		}
+	}
+}
+
+func FuzzTokenGetIndex(f *testing.F) {
+	gen := NewTokenGenerator(WithTokenSalt("salt"), WithHMACKey([]byte("secret")), WithTokenTTL(time.Hour))
+	f.Add(gen.ForIndex(0))
+	f.Add(gen.ForIndex(1 << 20))
+	f.Add("")
+	f.Add("====")
+	f.Fuzz(func(t *testing.T, token string) {
+		index, err := gen.GetIndex(token)
+		if err != nil {
+			return
+		}
+		if again := gen.ForIndex(index); again == "" {
+			t.Fatalf("expected a token for index %d", index)
+		}
+	})
+}
+
+type failingCodec struct{}
+
+func (failingCodec) Encode([]byte) (string, error) { return "", errStore }
+
+func (failingCodec) Decode(string) ([]byte, error) { return nil, ErrInvalidToken }
+
+var errStore = errors.New("store unavailable")
+
+func TestForIndexChecked(t *testing.T) {
+	gen := NewTokenGenerator(WithTokenCodec(failingCodec{}))
+
+	if _, err := gen.(CheckedTokenGenerator).ForIndexChecked(context.Background(), 10); !errors.Is(err, errStore) {
+		t.Fatalf("expected %v, got %v", errStore, err)
+	}
+	if _, _, err := SliceToken([]int{1, 2, 3}, gen, "", 2); !errors.Is(err, errStore) {
+		t.Fatalf("expected %v, got %v", errStore, err)
+	}
+	b := NewResponseBuilder(gen)
+	if _, err := BuildPage(b, PageRange{Limit: 2}, []int{1, 2, 3}); !errors.Is(err, errStore) {
+		t.Fatalf("expected %v, got %v", errStore, err)
+	}
+}
+
+func TestTokenSaltRotation(t *testing.T) {
+	old := NewTokenGenerator(WithTokenSalt("old"))
+	rotated := NewTokenGenerator(WithTokenSalts("new", "old"))
+
+	if index, err := rotated.GetIndex(old.ForIndex(7)); err != nil || index != 7 {
+		t.Fatalf("expected old-salt tokens to decode to %v, got %v, %v", 7, index, err)
+	}
+	token := rotated.ForIndex(9)
+	if _, err := old.GetIndex(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected new tokens to use the new salt, got %v", err)
+	}
+	if index, err := NewTokenGenerator(WithTokenSalt("new")).GetIndex(token); err != nil || index != 9 {
+		t.Fatalf("expected %v, got %v, %v", 9, index, err)
+	}
+	if _, err := rotated.GetIndex(NewTokenGenerator(WithTokenSalt("other")).ForIndex(1)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
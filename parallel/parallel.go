@@ -0,0 +1,127 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Option is parallel option.
+type Option func(*options)
+
+type options struct {
+	workers int
+}
+
+// WithWorkers sets the number of elements processed at once, GOMAXPROCS by default.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// IndexError is the error of the element at Index.
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e IndexError) Error() string {
+	return fmt.Sprintf("[%d] %v", e.Index, e.Err)
+}
+
+func (e IndexError) Unwrap() error {
+	return e.Err
+}
+
+// Errors holds the errors of the failed elements, sorted by index.
+type Errors []IndexError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("parallel: %d elements failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the elements, for errors.Is and errors.As.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Map calls fn on the elements of in concurrently and returns the results in
+// the order of in. Every element is processed even if others fail; the
+// results of the failed elements are zero and their errors are returned as
+// Errors. Once ctx is done, the remaining elements are skipped and the error
+// of ctx is returned.
+func Map[T, U any](ctx context.Context, in []T, fn func(ctx context.Context, v T) (U, error), opts ...Option) ([]U, error) {
+	out := make([]U, len(in))
+	err := run(ctx, len(in), func(i int) (err error) {
+		out[i], err = fn(ctx, in[i])
+		return err
+	}, opts)
+	return out, err
+}
+
+// ForEach calls fn on the elements of in concurrently, like Map.
+func ForEach[T any](ctx context.Context, in []T, fn func(ctx context.Context, v T) error, opts ...Option) error {
+	return run(ctx, len(in), func(i int) error {
+		return fn(ctx, in[i])
+	}, opts)
+}
+
+// FlatMap calls fn on the elements of in concurrently, like Map, and returns
+// the concatenation of the results in the order of in.
+func FlatMap[T, U any](ctx context.Context, in []T, fn func(ctx context.Context, v T) ([]U, error), opts ...Option) ([]U, error) {
+	out, err := Map(ctx, in, fn, opts...)
+	return slices.Concat(out...), err
+}
+
+// run calls fn with the indexes up to n on a bounded number of workers.
+func run(ctx context.Context, n int, fn func(i int) error, opts []Option) error {
+	o := options{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs Errors
+	)
+	for range min(max(o.workers, 1), n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				if err := fn(i); err != nil {
+					mu.Lock()
+					errs = append(errs, IndexError{Index: i, Err: err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b IndexError) int { return a.Index - b.Index })
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapPreservesOrder(t *testing.T) {
+	in := make([]int, 100)
+	for i := range in {
+		in[i] = i
+	}
+	var running, peak atomic.Int32
+	out, err := Map(context.Background(), in, func(ctx context.Context, v int) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(time.Duration(100-v) * 10 * time.Microsecond)
+		return strconv.Itoa(v), nil
+	}, WithWorkers(4))
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	for i, s := range out {
+		if s != strconv.Itoa(i) {
+			t.Fatalf("expected %v, got %v", i, s)
+		}
+	}
+	if peak.Load() > 4 {
+		t.Fatalf("expected at most %v workers, got %v", 4, peak.Load())
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	out, err := Map(context.Background(), []int{0, 1, 2, 3}, func(ctx context.Context, v int) (int, error) {
+		if v%2 == 1 {
+			return 0, errOdd
+		}
+		return v * 2, nil
+	})
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 3 {
+		t.Fatalf("expected the errors of elements 1 and 3, got %v", err)
+	}
+	if !errors.Is(err, errOdd) {
+		t.Fatalf("expected %v, got %v", errOdd, err)
+	}
+	if out[2] != 4 {
+		t.Fatalf("expected %v, got %v", 4, out[2])
+	}
+}
+
+func TestForEachCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	err := ForEach(ctx, make([]int, 100), func(ctx context.Context, v int) error {
+		if calls.Add(1) == 5 {
+			cancel()
+		}
+		return nil
+	}, WithWorkers(1))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if calls.Load() != 5 {
+		t.Fatalf("expected the remaining elements to be skipped, got %v calls", calls.Load())
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	out, err := FlatMap(context.Background(), []int{1, 2, 3}, func(ctx context.Context, v int) ([]int, error) {
+		return make([]int, v), nil
+	})
+	if err != nil || len(out) != 6 {
+		t.Fatalf("expected %v elements, got %v, %v", 6, len(out), err)
+	}
+}
@@ -0,0 +1,188 @@
+package periodic
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Overlap decides what happens when a run is due while the previous one is
+// still running.
+type Overlap int
+
+const (
+	// Skip drops the due run.
+	Skip Overlap = iota
+	// Queue starts the due run as soon as the previous one returns. At most
+	// one run is queued.
+	Queue
+)
+
+// Option is runner option.
+type Option func(*options)
+
+type options struct {
+	jitter    float64
+	overlap   Overlap
+	immediate bool
+	onError   func(err error)
+}
+
+// WithJitter delays each run by a random share of up to jitter of the
+// interval, e.g. 0.1 for up to 10%, so that replicas started together do not
+// run in lockstep.
+func WithJitter(jitter float64) Option {
+	return func(o *options) {
+		o.jitter = jitter
+	}
+}
+
+// WithOverlap sets what happens when a run is due while the previous one is
+// still running, Skip by default.
+func WithOverlap(overlap Overlap) Option {
+	return func(o *options) {
+		o.overlap = overlap
+	}
+}
+
+// WithImmediate runs the function as soon as the runner starts rather than
+// after the first interval.
+func WithImmediate() Option {
+	return func(o *options) {
+		o.immediate = true
+	}
+}
+
+// WithOnError sets a hook invoked with the errors of the runs, including
+// recovered panics.
+func WithOnError(fn func(err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// Runner calls a function every interval in the background.
+type Runner struct {
+	interval time.Duration
+	fn       func(ctx context.Context) error
+	opts     options
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	start    sync.Once
+	stop     sync.Once
+	quit     chan struct{}
+	stopped  chan struct{}
+	finished chan struct{}
+	wg       sync.WaitGroup
+}
+
+// New creates a Runner calling fn every interval once started.
+func New(interval time.Duration, fn func(ctx context.Context) error, opts ...Option) *Runner {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{
+		interval: interval,
+		fn:       fn,
+		opts:     o,
+		ctx:      ctx,
+		cancel:   cancel,
+		quit:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		finished: make(chan struct{}, 1),
+	}
+}
+
+// Start starts calling the function in the background. It is a no-op if
+// called more than once.
+func (r *Runner) Start() {
+	r.start.Do(func() { go r.loop() })
+}
+
+// Stop stops the runner and waits for the current run to return, or until
+// ctx is done, when the context of the run is canceled.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.stop.Do(func() { close(r.quit) })
+	r.Start() // lets a runner that was never started stop
+	done := make(chan struct{})
+	go func() {
+		<-r.stopped
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return ctx.Err()
+	}
+}
+
+func (r *Runner) loop() {
+	defer close(r.stopped)
+	var running, queued bool
+	run := func() {
+		running = true
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.run()
+			r.finished <- struct{}{}
+		}()
+	}
+	select {
+	case <-r.quit:
+		return
+	default:
+	}
+	if r.opts.immediate {
+		run()
+	}
+	t := time.NewTimer(r.next())
+	defer t.Stop()
+	for {
+		select {
+		case <-r.quit:
+			return
+		case <-t.C:
+			t.Reset(r.next())
+			if !running {
+				run()
+			} else if r.opts.overlap == Queue {
+				queued = true
+			}
+		case <-r.finished:
+			running = false
+			if queued {
+				queued = false
+				run()
+			}
+		}
+	}
+}
+
+// next returns the delay until the next run.
+func (r *Runner) next() time.Duration {
+	return r.interval + time.Duration(rand.Float64()*r.opts.jitter*float64(r.interval))
+}
+
+func (r *Runner) run() {
+	err := func() (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = fmt.Errorf("periodic: panic: %v", v)
+			}
+		}()
+		return r.fn(r.ctx)
+	}()
+	if err != nil && r.opts.onError != nil {
+		r.opts.onError(err)
+	}
+}
@@ -0,0 +1,75 @@
+package periodic
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunner(t *testing.T) {
+	var runs atomic.Int32
+	r := New(10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, WithImmediate(), WithJitter(0.1))
+	r.Start()
+	time.Sleep(55 * time.Millisecond)
+	if err := r.Stop(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	n := runs.Load()
+	if n < 3 || n > 6 {
+		t.Fatalf("expected about %v runs, got %v", 5, n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != n {
+		t.Fatalf("expected no run after Stop")
+	}
+}
+
+func TestRunnerOverlap(t *testing.T) {
+	for _, tc := range []struct {
+		overlap Overlap
+		runs    int32
+	}{{Skip, 1}, {Queue, 2}} {
+		// The first run overlaps two ticks, and returns before the third one.
+		var runs atomic.Int32
+		r := New(50*time.Millisecond, func(ctx context.Context) error {
+			if runs.Add(1) == 1 {
+				time.Sleep(120 * time.Millisecond)
+			}
+			return nil
+		}, WithImmediate(), WithOverlap(tc.overlap))
+		r.Start()
+		time.Sleep(140 * time.Millisecond)
+		r.Stop(context.Background())
+		if n := runs.Load(); n != tc.runs {
+			t.Fatalf("overlap %v: expected %v runs, got %v", tc.overlap, tc.runs, n)
+		}
+	}
+}
+
+func TestRunnerStopWaits(t *testing.T) {
+	started := make(chan struct{})
+	var errs atomic.Int32
+	r := New(time.Hour, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithImmediate(), WithOnError(func(err error) { errs.Add(1) }))
+	r.Start()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if err := r.Stop(context.Background()); err != nil {
+		t.Fatalf("expected the canceled run to return, got %v", err)
+	}
+	if errs.Load() != 1 {
+		t.Fatalf("expected the error to be reported, got %v", errs.Load())
+	}
+}
@@ -0,0 +1,171 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// StageOption is stage option.
+type StageOption func(*stageOptions)
+
+type stageOptions struct {
+	workers int
+	buffer  int
+}
+
+// WithWorkers sets the number of goroutines processing the values of a
+// stage, 1 by default. Values are emitted out of order with several workers.
+func WithWorkers(n int) StageOption {
+	return func(o *stageOptions) {
+		o.workers = n
+	}
+}
+
+// WithBuffer sets the size of the output channel of a stage, 0 by default.
+func WithBuffer(n int) StageOption {
+	return func(o *stageOptions) {
+		o.buffer = n
+	}
+}
+
+func newStageOptions(opts []StageOption) stageOptions {
+	o := stageOptions{workers: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.workers = max(o.workers, 1)
+	return o
+}
+
+// Pipeline runs stages connected by channels. The first error of a stage
+// cancels the context of the pipeline, stopping all of the stages.
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// New creates a Pipeline whose stages run with a context derived from ctx.
+func New(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Pipeline{ctx: ctx, cancel: cancel}
+}
+
+// Context returns the context of the stages, canceled on the first error.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Wait waits for all the stages to return and returns the first error, or
+// the error of the parent context if it was canceled.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.err
+	if err == nil {
+		err = p.ctx.Err()
+	}
+	p.cancel(err)
+	return err
+}
+
+func (p *Pipeline) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+		p.cancel(err)
+	}
+}
+
+// Source starts a stage producing values with fn, which passes them to emit.
+// emit blocks until the value is consumed and fails once the pipeline is
+// canceled. The returned channel is closed when fn returns.
+func Source[T any](p *Pipeline, fn func(ctx context.Context, emit func(T) error) error, opts ...StageOption) <-chan T {
+	o := newStageOptions(opts)
+	out := make(chan T, o.buffer)
+	emit := func(v T) error {
+		select {
+		case out <- v:
+			return nil
+		case <-p.ctx.Done():
+			return context.Cause(p.ctx)
+		}
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		if err := fn(p.ctx, emit); err != nil {
+			p.fail(err)
+		}
+	}()
+	return out
+}
+
+// Stage starts a stage transforming the values of in with fn on the
+// configured number of workers. The returned channel is closed once in is
+// drained or the pipeline is canceled.
+func Stage[T, U any](p *Pipeline, in <-chan T, fn func(ctx context.Context, v T) (U, error), opts ...StageOption) <-chan U {
+	o := newStageOptions(opts)
+	out := make(chan U, o.buffer)
+	run(p, in, o.workers, func(v T) bool {
+		u, err := fn(p.ctx, v)
+		if err != nil {
+			p.fail(err)
+			return false
+		}
+		select {
+		case out <- u:
+			return true
+		case <-p.ctx.Done():
+			return false
+		}
+	}, func() { close(out) })
+	return out
+}
+
+// Sink starts a stage consuming the values of in with fn on the configured
+// number of workers. The buffer option does not apply.
+func Sink[T any](p *Pipeline, in <-chan T, fn func(ctx context.Context, v T) error, opts ...StageOption) {
+	o := newStageOptions(opts)
+	run(p, in, o.workers, func(v T) bool {
+		if err := fn(p.ctx, v); err != nil {
+			p.fail(err)
+			return false
+		}
+		return true
+	}, func() {})
+}
+
+// run starts workers handling the values of in until it is drained, handle
+// returns false or the pipeline is canceled, calling done once they return.
+func run[T any](p *Pipeline, in <-chan T, workers int, handle func(v T) bool, done func()) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case v, ok := <-in:
+					if !ok || !handle(v) {
+						return
+					}
+				case <-p.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		wg.Wait()
+		done()
+	}()
+}
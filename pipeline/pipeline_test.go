@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	p := New(context.Background())
+	nums := Source(p, func(ctx context.Context, emit func(int) error) error {
+		for i := range 100 {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, WithBuffer(10))
+	strs := Stage(p, nums, func(ctx context.Context, v int) (string, error) {
+		return strconv.Itoa(v * 2), nil
+	}, WithWorkers(4), WithBuffer(10))
+	var (
+		mu  sync.Mutex
+		got []int
+	)
+	Sink(p, strs, func(ctx context.Context, s string) error {
+		n, err := strconv.Atoi(s)
+		mu.Lock()
+		got = append(got, n)
+		mu.Unlock()
+		return err
+	}, WithWorkers(2))
+	if err := p.Wait(); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	sort.Ints(got)
+	if len(got) != 100 || got[99] != 198 {
+		t.Fatalf("expected all values to be processed, got %v", got)
+	}
+}
+
+func TestPipelineShortCircuits(t *testing.T) {
+	errStage := errors.New("stage failed")
+	p := New(context.Background())
+	nums := Source(p, func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+	})
+	out := Stage(p, nums, func(ctx context.Context, v int) (int, error) {
+		if v == 10 {
+			return 0, errStage
+		}
+		return v, nil
+	})
+	Sink(p, out, func(ctx context.Context, v int) error { return nil })
+	done := make(chan error)
+	go func() { done <- p.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errStage) {
+			t.Fatalf("expected %v, got %v", errStage, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the pipeline to stop on error")
+	}
+}
+
+func TestPipelineParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := New(ctx)
+	nums := Source(p, func(ctx context.Context, emit func(int) error) error {
+		<-ctx.Done()
+		return nil
+	})
+	Sink(p, nums, func(ctx context.Context, v int) error { return nil })
+	cancel()
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,160 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrPoolFull is returned when the queue of a rejecting pool is full.
+	ErrPoolFull = errors.New("pool: queue full")
+	// ErrPoolClosed is returned when submitting to a pool that is shut down.
+	ErrPoolClosed = errors.New("pool: closed")
+)
+
+// Policy decides what Submit does when the queue is full.
+type Policy int
+
+const (
+	// Reject fails with ErrPoolFull.
+	Reject Policy = iota
+	// Block waits for room in the queue until the context is done.
+	Block
+)
+
+// Option is pool option.
+type Option func(*options)
+
+type options struct {
+	workers   int
+	queueSize int
+	policy    Policy
+	onPanic   func(v any)
+}
+
+// WithWorkers sets the number of workers, GOMAXPROCS by default.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// WithQueueSize sets the number of tasks waiting for a worker, 64 by default.
+func WithQueueSize(n int) Option {
+	return func(o *options) {
+		o.queueSize = n
+	}
+}
+
+// WithPolicy sets what Submit does when the queue is full, Reject by default.
+func WithPolicy(p Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithPanicHandler sets a hook invoked with the value of a task panic. The
+// worker recovers and keeps running tasks either way.
+func WithPanicHandler(fn func(v any)) Option {
+	return func(o *options) {
+		o.onPanic = fn
+	}
+}
+
+// Pool runs tasks on a fixed number of workers, queuing them while all the
+// workers are busy.
+type Pool struct {
+	opts  options
+	tasks chan func()
+	quit  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New creates a Pool and starts its workers.
+func New(opts ...Option) *Pool {
+	o := options{workers: runtime.GOMAXPROCS(0), queueSize: 64}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := &Pool{
+		opts:  o,
+		tasks: make(chan func(), max(o.queueSize, 0)),
+		quit:  make(chan struct{}),
+	}
+	for range max(o.workers, 1) {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+// Submit queues task to be run by a worker. When the queue is full, it fails
+// with ErrPoolFull or blocks until ctx is done, depending on the policy.
+func (p *Pool) Submit(ctx context.Context, task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	if p.opts.policy == Reject {
+		select {
+		case p.tasks <- task:
+			return nil
+		default:
+			return ErrPoolFull
+		}
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.quit:
+		return ErrPoolClosed
+	}
+}
+
+// Shutdown stops accepting tasks and waits until the queued and running tasks
+// are done or ctx is done. The workers exit once the queue is drained.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		close(p.quit)
+		p.mu.Lock()
+		p.closed = true
+		close(p.tasks)
+		p.mu.Unlock()
+	})
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task func()) {
+	defer func() {
+		if v := recover(); v != nil && p.opts.onPanic != nil {
+			p.opts.onPanic(v)
+		}
+	}()
+	task()
+}
@@ -0,0 +1,77 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolRunsTasks(t *testing.T) {
+	p := New(WithWorkers(4), WithQueueSize(100))
+	var n atomic.Int32
+	for range 100 {
+		if err := p.Submit(context.Background(), func() { n.Add(1) }); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if n.Load() != 100 {
+		t.Fatalf("expected %v, got %v", 100, n.Load())
+	}
+	if err := p.Submit(context.Background(), func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("expected %v, got %v", ErrPoolClosed, err)
+	}
+}
+
+func TestPoolPolicy(t *testing.T) {
+	release := make(chan struct{})
+	block := func() { <-release }
+	p := New(WithWorkers(1), WithQueueSize(1))
+	p.Submit(context.Background(), block)
+	time.Sleep(10 * time.Millisecond)
+	p.Submit(context.Background(), block)
+	if err := p.Submit(context.Background(), block); !errors.Is(err, ErrPoolFull) {
+		t.Fatalf("expected %v, got %v", ErrPoolFull, err)
+	}
+	close(release)
+	p.Shutdown(context.Background())
+
+	release = make(chan struct{})
+	p = New(WithWorkers(1), WithQueueSize(0), WithPolicy(Block))
+	p.Submit(context.Background(), block)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, block); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	close(release)
+	p.Shutdown(context.Background())
+}
+
+func TestPoolRecoversPanics(t *testing.T) {
+	var panics atomic.Int32
+	p := New(WithWorkers(1), WithPanicHandler(func(v any) { panics.Add(1) }))
+	p.Submit(context.Background(), func() { panic("boom") })
+	var ran atomic.Bool
+	p.Submit(context.Background(), func() { ran.Store(true) })
+	p.Shutdown(context.Background())
+	if panics.Load() != 1 || !ran.Load() {
+		t.Fatalf("expected the worker to survive the panic, got %v panics", panics.Load())
+	}
+}
+
+func TestPoolShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	p := New(WithWorkers(1))
+	p.Submit(context.Background(), func() { <-release })
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
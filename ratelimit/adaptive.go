@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AdaptiveOption is adaptive limiter option.
+type AdaptiveOption func(*Adaptive)
+
+// WithWindow overrides the window of observed latencies and throughput,
+// 10 seconds in 100 buckets by default.
+func WithWindow(window time.Duration, buckets int) AdaptiveOption {
+	return func(a *Adaptive) {
+		if window > 0 && buckets > 0 {
+			a.bucket = window / time.Duration(buckets)
+			a.buckets = make([]adaptiveBucket, buckets)
+		}
+	}
+}
+
+// WithCPUThreshold makes the limiter shed load only while usage, e.g. the CPU
+// usage sampled from cgroups in per mille, reports at least threshold.
+func WithCPUThreshold(usage func() float64, threshold float64) AdaptiveOption {
+	return func(a *Adaptive) {
+		a.usage = usage
+		a.threshold = threshold
+	}
+}
+
+// Adaptive is a concurrency limiter in the style of TCP BBR. It estimates the
+// number of requests the service can hold in flight as its maximum throughput
+// times its minimum latency over the window, and rejects requests beyond it,
+// shedding load automatically under overload.
+type Adaptive struct {
+	mu        sync.Mutex
+	bucket    time.Duration
+	buckets   []adaptiveBucket
+	inFlight  atomic.Int64
+	usage     func() float64
+	threshold float64
+}
+
+type adaptiveBucket struct {
+	start  time.Time
+	passes int64
+	rt     time.Duration
+}
+
+// NewAdaptive creates an Adaptive limiter.
+func NewAdaptive(opts ...AdaptiveOption) *Adaptive {
+	a := &Adaptive{
+		bucket:  100 * time.Millisecond,
+		buckets: make([]adaptiveBucket, 100),
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// Allow admits a request, returning the function to call with its result when
+// it is done, or ErrLimitExceeded if the service is overloaded.
+func (a *Adaptive) Allow() (done func(err error), err error) {
+	return a.AllowPriority(PriorityDefault)
+}
+
+// AllowContext is like AllowPriority with the priority stored in ctx.
+func (a *Adaptive) AllowContext(ctx context.Context) (done func(err error), err error) {
+	return a.AllowPriority(PriorityFromContext(ctx))
+}
+
+// AllowPriority is like Allow for a request of priority p. As the limit is
+// approached, sheddable requests are rejected first, while critical requests
+// are admitted somewhat beyond it.
+func (a *Adaptive) AllowPriority(p Priority) (done func(err error), err error) {
+	if a.overloaded(p) {
+		return nil, ErrLimitExceeded
+	}
+	a.inFlight.Add(1)
+	start := time.Now()
+	return func(error) {
+		a.inFlight.Add(-1)
+		a.record(start, time.Since(start))
+	}, nil
+}
+
+// InFlight returns the number of admitted requests not done yet.
+func (a *Adaptive) InFlight() int64 {
+	return a.inFlight.Load()
+}
+
+// MaxInFlight returns the current estimate of the number of requests the
+// service can hold in flight.
+func (a *Adaptive) MaxInFlight() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	var (
+		maxPass int64
+		minRT   = time.Duration(math.MaxInt64)
+	)
+	for _, b := range a.buckets {
+		// Only the complete buckets within the window are representative.
+		if b.passes == 0 || now.Sub(b.start) < a.bucket || now.Sub(b.start) > a.window() {
+			continue
+		}
+		maxPass = max(maxPass, b.passes)
+		minRT = min(minRT, b.rt/time.Duration(b.passes))
+	}
+	if maxPass == 0 {
+		return math.MaxInt64
+	}
+	perSecond := float64(maxPass) / a.bucket.Seconds()
+	return max(int64(math.Ceil(perSecond*minRT.Seconds())), 1)
+}
+
+func (a *Adaptive) overloaded(p Priority) bool {
+	if a.usage != nil && a.usage() < a.threshold {
+		return false
+	}
+	inFlight := a.inFlight.Load()
+	if inFlight <= 1 {
+		return false
+	}
+	limit := a.MaxInFlight()
+	if limit == math.MaxInt64 {
+		return false
+	}
+	return float64(inFlight) >= math.Max(float64(limit)*priorityShare(p), 1)
+}
+
+func (a *Adaptive) record(start time.Time, rt time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := start.Add(rt)
+	i := int(now.UnixNano()/int64(a.bucket)) % len(a.buckets)
+	b := &a.buckets[i]
+	if bucketStart := now.Truncate(a.bucket); !b.start.Equal(bucketStart) {
+		*b = adaptiveBucket{start: bucketStart}
+	}
+	b.passes++
+	b.rt += rt
+}
+
+func (a *Adaptive) window() time.Duration {
+	return a.bucket * time.Duration(len(a.buckets))
+}
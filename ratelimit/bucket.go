@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a Limiter refilling up to burst tokens at rate tokens per
+// second, each event taking a token. The rate and burst can be updated at
+// runtime.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+var _ Limiter = (*TokenBucket)(nil)
+
+// NewTokenBucket creates a TokenBucket allowing rate events per second with
+// bursts of up to burst events. The bucket starts full.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow reports whether an event may happen now.
+func (b *TokenBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, taking their tokens if so.
+func (b *TokenBucket) AllowN(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// Wait blocks until an event may happen or ctx is done. It fails fast with
+// ErrLimitExceeded when the wait would exceed the deadline of ctx.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return b.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n events may happen or ctx is done.
+func (b *TokenBucket) WaitN(ctx context.Context, n int) error {
+	return b.ReserveN(n).Wait(ctx)
+}
+
+// Reserve reserves a token, returning when the event may happen.
+func (b *TokenBucket) Reserve() *Reservation {
+	return b.ReserveN(1)
+}
+
+// ReserveN reserves n tokens, returning when the events may happen. The
+// reservation is not ok when n exceeds the burst.
+func (b *TokenBucket) ReserveN(n int) *Reservation {
+	delay, ok := b.reserve(n)
+	return NewReservation(ok, delay, func() { b.cancel(n) })
+}
+
+// SetRate updates the number of tokens refilled per second.
+func (b *TokenBucket) SetRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	b.rate = rate
+}
+
+// SetBurst updates the maximum number of tokens.
+func (b *TokenBucket) SetBurst(burst int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	b.burst = burst
+	b.tokens = min(b.tokens, float64(burst))
+}
+
+// reserve takes n tokens in advance, returning the delay until they are
+// refilled. It fails when n exceeds the burst or tokens are never refilled.
+func (b *TokenBucket) reserve(n int) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	if n > b.burst {
+		return 0, false
+	}
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0, true
+	}
+	if b.rate <= 0 {
+		b.tokens += float64(n)
+		return 0, false
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), true
+}
+
+// cancel returns n reserved tokens.
+func (b *TokenBucket) cancel(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	b.tokens = min(b.tokens+float64(n), float64(b.burst))
+}
+
+// advance refills the tokens for the time elapsed since the last update.
+func (b *TokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 && b.rate > 0 {
+		b.tokens = min(b.tokens+elapsed.Seconds()*b.rate, float64(b.burst))
+	}
+	b.last = now
+}
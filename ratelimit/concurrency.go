@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueFull is returned by Concurrency.Acquire when too many callers are
+// waiting already.
+var ErrQueueFull = errors.New("ratelimit: queue full")
+
+// ConcurrencyOption is concurrency limiter option.
+type ConcurrencyOption func(*Concurrency)
+
+// WithQueueSize limits the number of callers waiting in Acquire; further
+// callers fail with ErrQueueFull. The queue is unbounded by default.
+func WithQueueSize(n int) ConcurrencyOption {
+	return func(c *Concurrency) {
+		if n >= 0 {
+			c.queueSize = n
+		}
+	}
+}
+
+// WithQueueTimeout limits the time callers wait in Acquire; callers waiting
+// longer fail with ErrLimitExceeded.
+func WithQueueTimeout(d time.Duration) ConcurrencyOption {
+	return func(c *Concurrency) {
+		if d > 0 {
+			c.queueTimeout = d
+		}
+	}
+}
+
+// Concurrency bounds the number of operations running simultaneously, such
+// as expensive report generations, independently of their rate.
+type Concurrency struct {
+	sem          chan struct{}
+	waiting      atomic.Int64
+	queueSize    int
+	queueTimeout time.Duration
+}
+
+// NewConcurrency creates a Concurrency limiter allowing limit operations in flight.
+func NewConcurrency(limit int, opts ...ConcurrencyOption) *Concurrency {
+	c := &Concurrency{sem: make(chan struct{}, limit), queueSize: -1}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// TryAcquire acquires a slot without waiting, reporting whether it succeeded.
+func (c *Concurrency) TryAcquire() bool {
+	select {
+	case c.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Acquire waits for a slot until ctx is done or the queue timeout expires.
+// Every successful Acquire must be followed by a Release.
+func (c *Concurrency) Acquire(ctx context.Context) error {
+	if c.TryAcquire() {
+		return nil
+	}
+	if n := c.waiting.Add(1); c.queueSize >= 0 && n > int64(c.queueSize) {
+		c.waiting.Add(-1)
+		return ErrQueueFull
+	}
+	defer c.waiting.Add(-1)
+	var timeout <-chan time.Time
+	if c.queueTimeout > 0 {
+		t := time.NewTimer(c.queueTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	case <-timeout:
+		return ErrLimitExceeded
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release releases a slot acquired with Acquire or TryAcquire.
+func (c *Concurrency) Release() {
+	<-c.sem
+}
+
+// InFlight returns the number of acquired slots.
+func (c *Concurrency) InFlight() int {
+	return len(c.sem)
+}
@@ -0,0 +1,104 @@
+// Package grpclimit rate limits gRPC servers with the ratelimit package.
+package grpclimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/go-kratos/kit/ratelimit"
+)
+
+// Option is interceptor option.
+type Option func(*options)
+
+// WithRetryAfter overrides the delay advertised to rejected clients when the
+// limiter reports none, 1 second by default.
+func WithRetryAfter(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.retryAfter = d
+		}
+	}
+}
+
+type options struct {
+	retryAfter time.Duration
+}
+
+// KeyFunc derives the rate limiting key of a call, e.g. the peer address or
+// the authenticated user.
+type KeyFunc func(ctx context.Context, fullMethod string) string
+
+// UnaryServerInterceptor returns a unary interceptor rejecting the calls l
+// does not allow with ResourceExhausted, carrying the retry delay derived with
+// ratelimit.RetryAfter in RetryInfo details and the retry-after header.
+func UnaryServerInterceptor(l ratelimit.Limiter, opts ...Option) grpc.UnaryServerInterceptor {
+	return unary(func(context.Context, string) ratelimit.Limiter { return l }, opts)
+}
+
+// KeyedUnaryServerInterceptor is like UnaryServerInterceptor, limiting the
+// calls of each key separately.
+func KeyedUnaryServerInterceptor(k *ratelimit.Keyed[string], key KeyFunc, opts ...Option) grpc.UnaryServerInterceptor {
+	return unary(func(ctx context.Context, method string) ratelimit.Limiter { return k.Get(key(ctx, method)) }, opts)
+}
+
+// StreamServerInterceptor returns a stream interceptor rejecting the streams
+// l does not allow like UnaryServerInterceptor.
+func StreamServerInterceptor(l ratelimit.Limiter, opts ...Option) grpc.StreamServerInterceptor {
+	return stream(func(context.Context, string) ratelimit.Limiter { return l }, opts)
+}
+
+// KeyedStreamServerInterceptor is like StreamServerInterceptor, limiting the
+// streams of each key separately.
+func KeyedStreamServerInterceptor(k *ratelimit.Keyed[string], key KeyFunc, opts ...Option) grpc.StreamServerInterceptor {
+	return stream(func(ctx context.Context, method string) ratelimit.Limiter { return k.Get(key(ctx, method)) }, opts)
+}
+
+func unary(limiter func(context.Context, string) ratelimit.Limiter, opts []Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if l := limiter(ctx, info.FullMethod); !l.Allow() {
+			return nil, o.exhausted(ctx, l)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func stream(limiter func(context.Context, string) ratelimit.Limiter, opts []Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if l := limiter(ss.Context(), info.FullMethod); !l.Allow() {
+			return o.exhausted(ss.Context(), l)
+		}
+		return handler(srv, ss)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{retryAfter: time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// exhausted sets the retry-after header to the delay l reports and returns
+// the ResourceExhausted error.
+func (o *options) exhausted(ctx context.Context, l ratelimit.Limiter) error {
+	d := ratelimit.RetryAfter(l, o.retryAfter)
+	secs := int((d + time.Second - 1) / time.Second)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(secs)))
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	if ds, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(d)}); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
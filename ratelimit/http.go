@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MiddlewareOption is middleware option.
+type MiddlewareOption func(*middleware)
+
+// WithRetryAfter overrides the delay advertised to rejected clients in the
+// Retry-After header when the limiter reports none, 1 second by default.
+func WithRetryAfter(d time.Duration) MiddlewareOption {
+	return func(m *middleware) {
+		if d > 0 {
+			m.retryAfter = d
+		}
+	}
+}
+
+type middleware struct {
+	retryAfter time.Duration
+}
+
+// Middleware returns a net/http middleware, which is also usable as a kratos
+// HTTP transport filter, answering the requests l does not allow with
+// 429 Too Many Requests and a Retry-After header derived with RetryAfter.
+func Middleware(l Limiter, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	return newMiddleware(func(*http.Request) Limiter { return l }, opts)
+}
+
+// KeyedMiddleware is like Middleware, limiting the requests of each key
+// derived from the request, e.g. the client IP or user, separately.
+func KeyedMiddleware(k *Keyed[string], key func(*http.Request) string, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	return newMiddleware(func(r *http.Request) Limiter { return k.Get(key(r)) }, opts)
+}
+
+func newMiddleware(limiter func(*http.Request) Limiter, opts []MiddlewareOption) func(http.Handler) http.Handler {
+	m := middleware{retryAfter: time.Second}
+	for _, o := range opts {
+		o(&m)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if l := limiter(r); !l.Allow() {
+				d := RetryAfter(l, m.retryAfter)
+				w.Header().Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kit/container/maps"
+)
+
+// defaultKeyedTTL is the idle TTL of Keyed limiters given a non-positive one.
+const defaultKeyedTTL = 10 * time.Minute
+
+// Keyed maintains a Limiter per key, such as per user, IP or tenant. Limiters
+// are created on first use and evicted once idle for the TTL, so that memory
+// does not grow with the number of keys ever seen.
+type Keyed[K comparable] struct {
+	// mu is held for reading to mark a limiter used and for writing to create
+	// or evict one, so that a limiter is never evicted while it is handed out.
+	mu         sync.RWMutex
+	limiters   maps.Map[K, *keyedLimiter]
+	newLimiter func(key K) Limiter
+	ttl        time.Duration
+	lastSweep  atomic.Int64
+}
+
+type keyedLimiter struct {
+	Limiter
+	used atomic.Int64
+}
+
+// NewKeyed creates a Keyed limiter creating the limiters of keys with
+// newLimiter and evicting them once idle for ttl. A non-positive ttl defaults
+// to ten minutes.
+func NewKeyed[K comparable](newLimiter func(key K) Limiter, ttl time.Duration) *Keyed[K] {
+	if ttl <= 0 {
+		ttl = defaultKeyedTTL
+	}
+	k := &Keyed[K]{newLimiter: newLimiter, ttl: ttl}
+	k.lastSweep.Store(time.Now().UnixNano())
+	return k
+}
+
+// Get returns the limiter of key, creating it if needed.
+func (k *Keyed[K]) Get(key K) Limiter {
+	now := time.Now().UnixNano()
+	k.sweep(now)
+	k.mu.RLock()
+	l, ok := k.limiters.Load(key)
+	if ok {
+		l.used.Store(now)
+	}
+	k.mu.RUnlock()
+	if ok {
+		return l.Limiter
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	l, ok = k.limiters.Load(key)
+	if !ok {
+		l = &keyedLimiter{Limiter: k.newLimiter(key)}
+		k.limiters.Store(key, l)
+	}
+	l.used.Store(now)
+	return l.Limiter
+}
+
+// Allow reports whether an event of key may happen now.
+func (k *Keyed[K]) Allow(key K) bool {
+	return k.Get(key).Allow()
+}
+
+// AllowN reports whether n events of key may happen now.
+func (k *Keyed[K]) AllowN(key K, n int) bool {
+	return k.Get(key).AllowN(n)
+}
+
+// Reserve reserves an event of key, returning when it may happen.
+func (k *Keyed[K]) Reserve(key K) *Reservation {
+	return k.Get(key).Reserve()
+}
+
+// Wait blocks until an event of key may happen or ctx is done.
+func (k *Keyed[K]) Wait(ctx context.Context, key K) error {
+	return k.Get(key).Wait(ctx)
+}
+
+// Len returns the number of keys with a limiter.
+func (k *Keyed[K]) Len() int {
+	n := 0
+	k.limiters.Range(func(K, *keyedLimiter) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// sweep evicts the idle limiters, at most once per TTL.
+func (k *Keyed[K]) sweep(now int64) {
+	last := k.lastSweep.Load()
+	if now-last < int64(k.ttl) || !k.lastSweep.CompareAndSwap(last, now) {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.limiters.Range(func(key K, l *keyedLimiter) bool {
+		if now-l.used.Load() >= int64(k.ttl) {
+			k.limiters.CompareAndDelete(key, l)
+		}
+		return true
+	})
+}
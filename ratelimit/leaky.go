@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LeakyBucket is a Limiter pacing events evenly at rate events per second,
+// without bursts. Events arriving early are queued, up to capacity events,
+// by delaying them until their turn.
+type LeakyBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	capacity int
+	next     time.Time
+}
+
+var _ Limiter = (*LeakyBucket)(nil)
+
+// NewLeakyBucket creates a LeakyBucket pacing rate events per second and
+// queueing up to capacity events. It panics if rate is not positive.
+func NewLeakyBucket(rate float64, capacity int) *LeakyBucket {
+	if rate <= 0 {
+		panic("ratelimit: leaky bucket rate must be positive")
+	}
+	return &LeakyBucket{interval: time.Duration(float64(time.Second) / rate), capacity: capacity}
+}
+
+// Take schedules an event regardless of the capacity and returns the time to
+// wait before it may happen.
+func (b *LeakyBucket) Take() time.Duration {
+	d, _, _ := b.schedule(1, -1)
+	return d
+}
+
+// Allow reports whether an event may happen now.
+func (b *LeakyBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, scheduling them if so.
+// Events are spaced by the pacing interval, so more than one event never may
+// happen now; ReserveN schedules them instead.
+func (b *LeakyBucket) AllowN(n int) bool {
+	_, _, ok := b.schedule(n, 0)
+	return ok
+}
+
+// Wait blocks until an event may happen or ctx is done. It fails with
+// ErrLimitExceeded when the queue is full.
+func (b *LeakyBucket) Wait(ctx context.Context) error {
+	return b.Reserve().Wait(ctx)
+}
+
+// Reserve schedules an event, returning when it may happen. The reservation
+// is not ok when the queue is full.
+func (b *LeakyBucket) Reserve() *Reservation {
+	return b.ReserveN(1)
+}
+
+// ReserveN schedules n events like Reserve, spaced by the pacing interval.
+// The reservation is not ok when the last of them would exceed the queue.
+// Canceling it frees the slots only if no event was scheduled after them.
+func (b *LeakyBucket) ReserveN(n int) *Reservation {
+	d, end, ok := b.schedule(n, b.capacity)
+	return NewReservation(ok, d, func() { b.unschedule(n, end) })
+}
+
+// schedule schedules n events spaced by the interval if no more than queued
+// events are ahead of the last one, or without limit if queued is negative.
+// It returns the delay of the first event and the end of the schedule.
+func (b *LeakyBucket) schedule(n, queued int) (time.Duration, time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	at := b.next
+	if at.Before(now) {
+		at = now
+	}
+	d := at.Sub(now)
+	if queued >= 0 && d+time.Duration(max(n-1, 0))*b.interval > time.Duration(queued)*b.interval {
+		return 0, time.Time{}, false
+	}
+	b.next = at.Add(time.Duration(n) * b.interval)
+	return d, b.next, true
+}
+
+// unschedule frees the slots of n canceled events ending the schedule at
+// end. Slots followed by later reservations are kept, so that those are not
+// moved ahead of their turn.
+func (b *LeakyBucket) unschedule(n int, end time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.next.Equal(end) {
+		b.next = end.Add(-time.Duration(n) * b.interval)
+	}
+}
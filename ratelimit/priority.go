@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+)
+
+// Priority is the importance of a request, deciding the order in which
+// requests are shed under overload.
+type Priority int
+
+const (
+	// PrioritySheddable requests, such as prefetches and batch jobs, are shed first.
+	PrioritySheddable Priority = iota - 1
+	// PriorityDefault is the priority of requests without an explicit one.
+	PriorityDefault
+	// PriorityCritical requests, such as user-facing writes, are shed last.
+	PriorityCritical
+)
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch {
+	case p < PriorityDefault:
+		return "sheddable"
+	case p > PriorityDefault:
+		return "critical"
+	}
+	return "default"
+}
+
+// ParsePriority parses the name of a priority, e.g. from a request header or
+// metadata, reporting false for unknown names.
+func ParsePriority(s string) (Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "sheddable":
+		return PrioritySheddable, true
+	case "default":
+		return PriorityDefault, true
+	case "critical":
+		return PriorityCritical, true
+	}
+	return PriorityDefault, false
+}
+
+type priorityKey struct{}
+
+// NewPriorityContext returns a new Context that carries the priority.
+func NewPriorityContext(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority stored in ctx, or PriorityDefault.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// priorityShare returns the share of the estimated limit requests of priority
+// p may fill: sheddable requests are rejected before saturation, and critical
+// ones may exceed the estimate.
+func priorityShare(p Priority) float64 {
+	switch {
+	case p < PriorityDefault:
+		return 0.75
+	case p > PriorityDefault:
+		return 1.25
+	}
+	return 1
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is returned when an event cannot be admitted, e.g. because
+// the wait exceeds the context deadline.
+var ErrLimitExceeded = errors.New("ratelimit: limit exceeded")
+
+// Limiter controls how frequently events are allowed to happen.
+type Limiter interface {
+	// Allow reports whether an event may happen now.
+	Allow() bool
+	// AllowN reports whether n events may happen now.
+	AllowN(n int) bool
+	// Reserve reserves an event, returning when it may happen.
+	Reserve() *Reservation
+	// Wait blocks until an event may happen or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// Reservation holds events admitted ahead of time by a Limiter.
+type Reservation struct {
+	ok     bool
+	at     time.Time
+	once   sync.Once
+	cancel func()
+}
+
+// NewReservation creates a Reservation admitted after delay, whose events are
+// returned to the limiter with cancel. A Reservation that is not ok never
+// admits its events.
+func NewReservation(ok bool, delay time.Duration, cancel func()) *Reservation {
+	return &Reservation{ok: ok, at: time.Now().Add(delay), cancel: cancel}
+}
+
+// OK reports whether the events are admitted, e.g. false when they exceed the
+// burst of the limiter.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns the time to wait until the events may happen, or
+// math.MaxInt64 if they are not admitted.
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return math.MaxInt64
+	}
+	return max(time.Until(r.at), 0)
+}
+
+// Cancel returns the reserved events to the limiter, so that other events
+// may use them. It is a no-op if called more than once.
+func (r *Reservation) Cancel() {
+	if !r.ok || r.cancel == nil {
+		return
+	}
+	r.once.Do(r.cancel)
+}
+
+// RetryAfter returns how long a caller rejected by l should wait before
+// retrying, taken from the delay of a reservation that is canceled at once.
+// It returns fallback when l reports no delay, e.g. when the event exceeds the
+// burst or the limiter only admits events that may happen now.
+func RetryAfter(l Limiter, fallback time.Duration) time.Duration {
+	r := l.Reserve()
+	defer r.Cancel()
+	if d := r.Delay(); r.OK() && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// Wait blocks until the events may happen or ctx is done, canceling the
+// reservation in the latter case. It fails fast with ErrLimitExceeded when
+// the events are not admitted or the delay exceeds the deadline of ctx.
+func (r *Reservation) Wait(ctx context.Context) error {
+	if !r.ok {
+		return ErrLimitExceeded
+	}
+	d := r.Delay()
+	if d <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		r.Cancel()
+		return ErrLimitExceeded
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,361 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	b := NewTokenBucket(1, 3)
+	if !b.AllowN(3) {
+		t.Fatalf("expected burst of 3 to be allowed")
+	}
+	if b.Allow() {
+		t.Fatalf("expected empty bucket to reject")
+	}
+	b.SetRate(1000)
+	time.Sleep(5 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatalf("expected bucket to refill at the updated rate")
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	b := NewTokenBucket(100, 1)
+	b.Allow()
+
+	start := time.Now()
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Fatalf("expected Wait to pace the event, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := NewTokenBucket(0.1, 1).WaitN(ctx, 1); err != nil {
+		t.Fatalf("expected token from the full bucket, got %v", err)
+	}
+	slow := NewTokenBucket(0.1, 1)
+	slow.Allow()
+	if err := slow.Wait(ctx); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected %v, got %v", ErrLimitExceeded, err)
+	}
+}
+
+func TestSlidingWindowLog(t *testing.T) {
+	l := NewSlidingWindowLog(2, 20*time.Millisecond)
+	if !l.Allow() || !l.Allow() {
+		t.Fatalf("expected 2 events to be allowed")
+	}
+	if l.Allow() {
+		t.Fatalf("expected third event in the window to be rejected")
+	}
+	start := time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected Wait to last until the window slides, took %v", elapsed)
+	}
+}
+
+func TestSlidingWindowCounter(t *testing.T) {
+	c := NewSlidingWindowCounter(4, 20*time.Millisecond)
+	if !c.AllowN(4) {
+		t.Fatalf("expected 4 events to be allowed")
+	}
+	if c.Allow() {
+		t.Fatalf("expected fifth event in the window to be rejected")
+	}
+	if err := c.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned unexpected error: %v", err)
+	}
+	if c.AllowN(5) {
+		t.Fatalf("expected events exceeding the limit to be rejected")
+	}
+}
+
+func TestAdaptiveShedsBeyondEstimate(t *testing.T) {
+	a := NewAdaptive(WithWindow(100*time.Millisecond, 10))
+	for range 50 {
+		done, err := a.Allow()
+		if err != nil {
+			t.Fatalf("Allow returned unexpected error: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+		done(nil)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	limit := a.MaxInFlight()
+	if limit == math.MaxInt64 || limit < 1 {
+		t.Fatalf("expected an estimate from the observed requests, got %d", limit)
+	}
+	var dones []func(error)
+	for {
+		done, err := a.Allow()
+		if err != nil {
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("expected %v, got %v", ErrLimitExceeded, err)
+			}
+			break
+		}
+		dones = append(dones, done)
+		if int64(len(dones)) > limit+1 {
+			t.Fatalf("expected requests beyond %d in flight to be shed", limit)
+		}
+	}
+	for _, done := range dones {
+		done(nil)
+	}
+}
+
+func TestLeakyBucketPacing(t *testing.T) {
+	b := NewLeakyBucket(100, 1)
+	if d := b.Take(); d != 0 {
+		t.Fatalf("expected the first event without delay, got %v", d)
+	}
+	if d := b.Take(); d < 9*time.Millisecond || d > 10*time.Millisecond {
+		t.Fatalf("expected the second event paced by 10ms, got %v", d)
+	}
+	if b.Allow() {
+		t.Fatalf("expected an early event to be rejected")
+	}
+	if err := b.Wait(context.Background()); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected %v with a full queue, got %v", ErrLimitExceeded, err)
+	}
+}
+
+func TestLeakyBucketN(t *testing.T) {
+	b := NewLeakyBucket(100, 2)
+	if b.AllowN(2) {
+		t.Fatalf("expected two events not to happen at once")
+	}
+	if r := b.ReserveN(4); r.OK() {
+		t.Fatalf("expected events beyond the queue to be rejected")
+	}
+	first := b.ReserveN(3)
+	if !first.OK() || first.Delay() != 0 {
+		t.Fatalf("expected the events to be spaced from now, got %v", first.Delay())
+	}
+	if d := b.Take(); d < 29*time.Millisecond || d > 30*time.Millisecond {
+		t.Fatalf("expected the next event after the 3 spaced ones, got %v", d)
+	}
+	first.Cancel()
+	if d := b.Take(); d < 39*time.Millisecond || d > 40*time.Millisecond {
+		t.Fatalf("expected a non-tail cancel not to move later events, got %v", d)
+	}
+	tail := NewLeakyBucket(100, 2)
+	tail.ReserveN(2).Cancel()
+	if d := tail.Take(); d != 0 {
+		t.Fatalf("expected a tail cancel to free the slots, got %v", d)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic for a non-positive rate")
+		}
+	}()
+	NewLeakyBucket(0, 1)
+}
+
+func TestKeyedEvictsIdleKeys(t *testing.T) {
+	k := NewKeyed(func(string) Limiter { return NewTokenBucket(1, 1) }, 10*time.Millisecond)
+	if !k.Allow("alice") || k.Allow("alice") {
+		t.Fatalf("expected alice to be limited separately")
+	}
+	if !k.Allow("bob") {
+		t.Fatalf("expected bob to have a separate limiter")
+	}
+	if n := k.Len(); n != 2 {
+		t.Fatalf("expected 2 keys, got %d", n)
+	}
+	time.Sleep(20 * time.Millisecond)
+	k.Allow("carol")
+	if n := k.Len(); n != 1 {
+		t.Fatalf("expected idle keys to be evicted, got %d keys", n)
+	}
+}
+
+func TestKeyedConcurrentEviction(t *testing.T) {
+	k := NewKeyed(func(string) Limiter { return NewTokenBucket(1, 1) }, time.Microsecond)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				k.Allow("alice")
+			}
+		}()
+	}
+	wg.Wait()
+	if n := k.Len(); n > 1 {
+		t.Fatalf("expected at most 1 key, got %d", n)
+	}
+}
+
+func TestKeyedNonPositiveTTL(t *testing.T) {
+	k := NewKeyed(func(string) Limiter { return NewTokenBucket(1, 1) }, 0)
+	if !k.Allow("alice") || k.Allow("alice") {
+		t.Fatalf("expected alice to be limited with a default ttl")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	h := KeyedMiddleware(
+		NewKeyed(func(string) Limiter { return NewTokenBucket(0.2, 1) }, time.Minute),
+		func(r *http.Request) string { return r.Header.Get("X-User") },
+		WithRetryAfter(1500*time.Millisecond),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(user string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", user)
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := serve("alice"); rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	rec := serve("alice")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "5" {
+		t.Fatalf("expected 429 with Retry-After 5, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := serve("bob"); rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+// unreservable is a Limiter admitting nothing and reporting no delay.
+type unreservable struct{ Limiter }
+
+func (unreservable) Reserve() *Reservation { return NewReservation(false, 0, nil) }
+
+func TestRetryAfter(t *testing.T) {
+	b := NewTokenBucket(0.5, 1)
+	b.Allow()
+	if d := RetryAfter(b, time.Second); d < time.Second || d > 2*time.Second {
+		t.Fatalf("expected the bucket delay of about %v, got %v", 2*time.Second, d)
+	}
+	if d := RetryAfter(b, time.Second); d > 2*time.Second {
+		t.Fatalf("expected the reservation to be canceled, got %v", d)
+	}
+	if d := RetryAfter(unreservable{}, 3*time.Second); d != 3*time.Second {
+		t.Fatalf("expected the fallback %v, got %v", 3*time.Second, d)
+	}
+}
+
+func TestConcurrencyQueue(t *testing.T) {
+	c := NewConcurrency(1, WithQueueSize(1), WithQueueTimeout(10*time.Millisecond))
+	if !c.TryAcquire() {
+		t.Fatalf("expected a free slot")
+	}
+	if c.TryAcquire() {
+		t.Fatalf("expected no free slot")
+	}
+
+	queued := make(chan error)
+	go func() { queued <- c.Acquire(context.Background()) }()
+	time.Sleep(time.Millisecond)
+	if err := c.Acquire(context.Background()); !errors.Is(err, ErrQueueFull) && !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected %v, got %v", ErrQueueFull, err)
+	}
+	if err := <-queued; !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected %v after the queue timeout, got %v", ErrLimitExceeded, err)
+	}
+
+	go func() { queued <- c.Acquire(context.Background()) }()
+	c.Release()
+	if err := <-queued; err != nil {
+		t.Fatalf("expected the released slot to be acquired, got %v", err)
+	}
+	if n := c.InFlight(); n != 1 {
+		t.Fatalf("expected 1 slot in flight, got %d", n)
+	}
+}
+
+func TestAdaptiveShedsByPriority(t *testing.T) {
+	a := NewAdaptive(WithWindow(100*time.Millisecond, 10))
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				done, err := a.Allow()
+				if err != nil {
+					continue
+				}
+				time.Sleep(5 * time.Millisecond)
+				done(nil)
+			}
+		}()
+	}
+	wg.Wait()
+	time.Sleep(20 * time.Millisecond)
+
+	fill := func(p Priority) []func(error) {
+		var dones []func(error)
+		for {
+			done, err := a.AllowPriority(p)
+			if err != nil {
+				return dones
+			}
+			dones = append(dones, done)
+		}
+	}
+	sheddable := fill(PrioritySheddable)
+	critical := fill(PriorityCritical)
+	if len(critical) == 0 {
+		t.Fatalf("expected critical requests to be admitted after sheddable ones were shed")
+	}
+	for _, done := range append(sheddable, critical...) {
+		done(nil)
+	}
+
+	ctx := NewPriorityContext(context.Background(), PriorityCritical)
+	if p := PriorityFromContext(ctx); p != PriorityCritical {
+		t.Fatalf("expected %v, got %v", PriorityCritical, p)
+	}
+	if p, ok := ParsePriority("Sheddable"); !ok || p != PrioritySheddable {
+		t.Fatalf("expected %v, got %v", PrioritySheddable, p)
+	}
+}
+
+func TestReservations(t *testing.T) {
+	limiters := map[string]Limiter{
+		"token bucket":    NewTokenBucket(100, 1),
+		"leaky bucket":    NewLeakyBucket(100, 1),
+		"sliding log":     NewSlidingWindowLog(1, 10*time.Millisecond),
+		"sliding counter": NewSlidingWindowCounter(1, 10*time.Millisecond),
+	}
+	for name, l := range limiters {
+		if r := l.Reserve(); !r.OK() || r.Delay() != 0 {
+			t.Fatalf("%s: expected an immediate reservation, got delay %v", name, r.Delay())
+		}
+		r := l.Reserve()
+		if !r.OK() || r.Delay() <= 0 {
+			t.Fatalf("%s: expected a delayed reservation, got delay %v", name, r.Delay())
+		}
+		r.Cancel()
+		if err := r.Wait(context.Background()); err != nil {
+			t.Fatalf("%s: Wait returned unexpected error: %v", name, err)
+		}
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("%s: Wait returned unexpected error: %v", name, err)
+		}
+	}
+	if r := NewTokenBucket(1, 1).ReserveN(2); r.OK() {
+		t.Fatalf("expected a reservation exceeding the burst to fail")
+	}
+}
@@ -0,0 +1,245 @@
+// Package redis implements distributed rate limiters shared through Redis.
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/go-kratos/kit/ratelimit"
+)
+
+// fixedWindow admits ARGV[1] events if the count of the window stored at
+// KEYS[1] stays within ARGV[2], expiring the window after ARGV[3]
+// milliseconds. A window left without expiry is expired anew. It returns
+// whether the events are admitted and otherwise the milliseconds until the
+// window ends.
+var fixedWindow = goredis.NewScript(`
+local n = tonumber(ARGV[1])
+local count = redis.call("INCRBY", KEYS[1], n)
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	ttl = tonumber(ARGV[3])
+end
+if count > tonumber(ARGV[2]) then
+	redis.call("DECRBY", KEYS[1], n)
+	return {0, ttl}
+end
+return {1, 0}
+`)
+
+// gcra admits ARGV[1] events with the generic cell rate algorithm, emitting
+// an event every ARGV[2] milliseconds with a tolerance of ARGV[3]
+// milliseconds for bursts. The theoretical arrival time is stored at KEYS[1].
+// It returns whether the events are admitted and otherwise the milliseconds
+// until they may be. With ARGV[4] set to 1, events that are not admitted yet
+// are reserved, returning whether they are admitted and their delay.
+var gcra = goredis.NewScript(`
+local n = tonumber(ARGV[1])
+local emission = tonumber(ARGV[2])
+local tolerance = tonumber(ARGV[3])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+local tat = math.max(tonumber(redis.call("GET", KEYS[1])) or now, now)
+local next = tat + n * emission
+local allowAt = next - tolerance
+local delay = math.max(math.ceil(allowAt - now), 0)
+if delay > 0 and ARGV[4] ~= "1" then
+	return {0, delay}
+end
+redis.call("SET", KEYS[1], string.format("%.3f", next), "PX", math.ceil(next - now))
+return {1, delay}
+`)
+
+// Option is limiter option.
+type Option func(*Limiter)
+
+// WithFallback overrides the local limiter used while Redis is unreachable.
+func WithFallback(l ratelimit.Limiter) Option {
+	return func(o *Limiter) {
+		o.fallback = l
+	}
+}
+
+// WithTimeout overrides the timeout of the Redis calls of Allow and AllowN,
+// 100ms by default.
+func WithTimeout(d time.Duration) Option {
+	return func(o *Limiter) {
+		if d > 0 {
+			o.timeout = d
+		}
+	}
+}
+
+// WithCooldown overrides how long the limiter keeps using the fallback after
+// a failed Redis call before trying Redis again, one second by default.
+func WithCooldown(d time.Duration) Option {
+	return func(o *Limiter) {
+		if d > 0 {
+			o.cooldown = d
+		}
+	}
+}
+
+// Limiter is a ratelimit.Limiter sharing its quota through Redis, so that the
+// replicas of a service are limited together. When Redis is unreachable it
+// degrades to a local limiter, by default a token bucket with the same limit,
+// for a cooldown, so that callers do not each wait for the timeout.
+type Limiter struct {
+	client   goredis.Scripter
+	script   *goredis.Script
+	reserve  bool
+	key      string
+	args     []any
+	fallback ratelimit.Limiter
+	timeout  time.Duration
+	cooldown time.Duration
+	// downUntil is the Unix time in nanoseconds until which the fallback is
+	// used after a failure.
+	downUntil atomic.Int64
+}
+
+var _ ratelimit.Limiter = (*Limiter)(nil)
+
+// NewFixedWindow creates a Limiter allowing limit events per window stored
+// at key.
+func NewFixedWindow(client goredis.Scripter, key string, limit int, window time.Duration, opts ...Option) *Limiter {
+	return newLimiter(client, fixedWindow, key, []any{limit, window.Milliseconds()},
+		ratelimit.NewTokenBucket(float64(limit)/window.Seconds(), limit), opts)
+}
+
+// NewGCRA creates a Limiter allowing rate events per second with bursts of up
+// to burst events, using the generic cell rate algorithm with state at key.
+// It panics if rate or burst is not positive.
+func NewGCRA(client goredis.Scripter, key string, rate float64, burst int, opts ...Option) *Limiter {
+	if rate <= 0 || burst <= 0 {
+		panic("ratelimit/redis: GCRA rate and burst must be positive")
+	}
+	emission := 1000 / rate
+	l := newLimiter(client, gcra, key, []any{emission, emission * float64(burst-1)},
+		ratelimit.NewTokenBucket(rate, burst), opts)
+	l.reserve = true
+	return l
+}
+
+func newLimiter(client goredis.Scripter, script *goredis.Script, key string, args []any, fallback ratelimit.Limiter, opts []Option) *Limiter {
+	l := &Limiter{
+		client:   client,
+		script:   script,
+		key:      key,
+		args:     args,
+		fallback: fallback,
+		timeout:  100 * time.Millisecond,
+		cooldown: time.Second,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Allow reports whether an event may happen now.
+func (l *Limiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now.
+func (l *Limiter) AllowN(n int) bool {
+	if l.down() {
+		return l.fallback.AllowN(n)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+	ok, _, err := l.take(ctx, n, false)
+	if err != nil {
+		l.trip()
+		return l.fallback.AllowN(n)
+	}
+	return ok
+}
+
+// Reserve reserves an event, returning when it may happen. Fixed window
+// limiters only admit events that may happen now. The quota is shared, so
+// canceling the reservation does not return it.
+func (l *Limiter) Reserve() *ratelimit.Reservation {
+	if l.down() {
+		return l.fallback.Reserve()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+	ok, delay, err := l.take(ctx, 1, l.reserve)
+	if err != nil {
+		l.trip()
+		return l.fallback.Reserve()
+	}
+	return ratelimit.NewReservation(ok, delay, nil)
+}
+
+// Wait blocks until an event may happen or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		if l.down() {
+			return l.fallback.Wait(ctx)
+		}
+		ok, delay, err := l.take(ctx, 1, l.reserve)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			l.trip()
+			return l.fallback.Wait(ctx)
+		}
+		if ok {
+			return ratelimit.NewReservation(true, delay, nil).Wait(ctx)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return ratelimit.ErrLimitExceeded
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// minDelay is the delay reported for refused events when the script reports
+// none, so that Wait does not spin.
+const minDelay = 10 * time.Millisecond
+
+// down reports whether the fallback is used after a recent failure.
+func (l *Limiter) down() bool {
+	return time.Now().UnixNano() < l.downUntil.Load()
+}
+
+// trip switches to the fallback for the cooldown.
+func (l *Limiter) trip() {
+	l.downUntil.Store(time.Now().Add(l.cooldown).UnixNano())
+}
+
+// take runs the script for n events, returning whether they are admitted and
+// the delay until they may happen, reserving them if reserve is set.
+func (l *Limiter) take(ctx context.Context, n int, reserve bool) (bool, time.Duration, error) {
+	args := append([]any{n}, l.args...)
+	if reserve {
+		args = append(args, 1)
+	}
+	res, err := l.script.Run(ctx, l.client, []string{l.key}, args...).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("ratelimit/redis: unexpected script result %v", res)
+	}
+	ok, delay := res[0] == 1, max(time.Duration(res[1])*time.Millisecond, 0)
+	if !ok {
+		delay = max(delay, minDelay)
+	}
+	return ok, delay, nil
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestLimiterFallsBackWhenUnreachable(t *testing.T) {
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	l := NewGCRA(client, "test", 1, 2, WithTimeout(50*time.Millisecond))
+	if !l.Allow() || !l.Allow() {
+		t.Fatalf("expected the local fallback to allow the burst")
+	}
+	if l.Allow() {
+		t.Fatalf("expected the local fallback to enforce the limit")
+	}
+}
+
+// scripter returns the queued script results in order, or err if set.
+type scripter struct {
+	goredis.Scripter
+	results [][]any
+	err     error
+	calls   int
+}
+
+func (s *scripter) EvalSha(ctx context.Context, sha1 string, keys []string, args ...any) *goredis.Cmd {
+	s.calls++
+	if s.err != nil {
+		return goredis.NewCmdResult(nil, s.err)
+	}
+	res := s.results[min(s.calls-1, len(s.results)-1)]
+	return goredis.NewCmdResult(res, nil)
+}
+
+func TestLimiterCooldown(t *testing.T) {
+	client := &scripter{results: [][]any{{int64(1), int64(0)}}, err: errors.New("unreachable")}
+	l := NewGCRA(client, "test", 100, 10, WithCooldown(20*time.Millisecond))
+
+	l.Allow()
+	l.Allow()
+	if client.calls != 1 {
+		t.Fatalf("expected the fallback during the cooldown, got %v calls", client.calls)
+	}
+	client.err = nil
+	time.Sleep(30 * time.Millisecond)
+	l.Allow()
+	if client.calls != 2 {
+		t.Fatalf("expected Redis to be tried after the cooldown, got %v calls", client.calls)
+	}
+}
+
+func TestNewGCRAInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		rate  float64
+		burst int
+	}{{0, 1}, {-1, 1}, {1, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a panic for rate %v and burst %v", tt.rate, tt.burst)
+				}
+			}()
+			NewGCRA(&scripter{}, "test", tt.rate, tt.burst)
+		}()
+	}
+}
+
+func TestFixedWindowNegativeTTL(t *testing.T) {
+	client := &scripter{results: [][]any{{int64(0), int64(-1)}, {int64(1), int64(0)}}}
+	l := NewFixedWindow(client, "test", 1, time.Second)
+
+	start := time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if client.calls != 2 || time.Since(start) < minDelay {
+		t.Fatalf("expected one retry after %v, got %v calls in %v", minDelay, client.calls, time.Since(start))
+	}
+}
@@ -0,0 +1,215 @@
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SlidingWindowLog is a Limiter allowing up to limit events within any window,
+// logging the time of each admitted event. It enforces the limit exactly at
+// the cost of memory proportional to the limit.
+type SlidingWindowLog struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	log    []time.Time
+}
+
+var _ Limiter = (*SlidingWindowLog)(nil)
+
+// NewSlidingWindowLog creates a SlidingWindowLog allowing limit events per window.
+func NewSlidingWindowLog(limit int, window time.Duration) *SlidingWindowLog {
+	return &SlidingWindowLog{limit: limit, window: window}
+}
+
+// Allow reports whether an event may happen now.
+func (l *SlidingWindowLog) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, logging them if so.
+func (l *SlidingWindowLog) AllowN(n int) bool {
+	_, ok := l.take(n, false)
+	return ok
+}
+
+// Wait blocks until an event may happen or ctx is done.
+func (l *SlidingWindowLog) Wait(ctx context.Context) error {
+	return l.Reserve().Wait(ctx)
+}
+
+// Reserve reserves an event, returning when it may happen.
+func (l *SlidingWindowLog) Reserve() *Reservation {
+	return l.ReserveN(1)
+}
+
+// ReserveN reserves n events, logging them at the time they may happen. The
+// reservation is not ok when n exceeds the limit.
+func (l *SlidingWindowLog) ReserveN(n int) *Reservation {
+	at, ok := l.take(n, true)
+	return NewReservation(ok, time.Until(at), func() { l.cancel(at, n) })
+}
+
+// take logs n events at the time they fit in the window. Unless reserve is
+// set, only events fitting now are logged. It returns the time of the events.
+func (l *SlidingWindowLog) take(n int, reserve bool) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	i := 0
+	for i < len(l.log) && now.Sub(l.log[i]) >= l.window {
+		i++
+	}
+	l.log = l.log[i:]
+	if n > l.limit {
+		return now, false
+	}
+	at := now
+	if excess := len(l.log) + n - l.limit; excess > 0 {
+		// The log is sorted, also with reserved events logged in the future.
+		at = l.log[excess-1].Add(l.window)
+		if !reserve {
+			return at, false
+		}
+	}
+	for range n {
+		l.log = append(l.log, at)
+	}
+	return at, true
+}
+
+// cancel removes n events logged at the given time.
+func (l *SlidingWindowLog) cancel(at time.Time, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.log = slices.DeleteFunc(l.log, func(t time.Time) bool {
+		if n > 0 && t.Equal(at) {
+			n--
+			return true
+		}
+		return false
+	})
+}
+
+// SlidingWindowCounter is a Limiter allowing about limit events within any
+// window. It counts the events of fixed windows and weighs the count of the
+// previous window by its overlap with the sliding window, smoothing bursts at
+// window boundaries in constant memory.
+type SlidingWindowCounter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	start  time.Time
+	prev   int
+	curr   int
+	next   int
+}
+
+var _ Limiter = (*SlidingWindowCounter)(nil)
+
+// NewSlidingWindowCounter creates a SlidingWindowCounter allowing limit events per window.
+func NewSlidingWindowCounter(limit int, window time.Duration) *SlidingWindowCounter {
+	return &SlidingWindowCounter{limit: limit, window: window, start: time.Now()}
+}
+
+// Allow reports whether an event may happen now.
+func (c *SlidingWindowCounter) Allow() bool {
+	return c.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, counting them if so.
+func (c *SlidingWindowCounter) AllowN(n int) bool {
+	_, _, ok := c.take(n, false)
+	return ok
+}
+
+// Wait blocks until an event may happen or ctx is done.
+func (c *SlidingWindowCounter) Wait(ctx context.Context) error {
+	return c.Reserve().Wait(ctx)
+}
+
+// Reserve reserves an event, returning when it may happen.
+func (c *SlidingWindowCounter) Reserve() *Reservation {
+	return c.ReserveN(1)
+}
+
+// ReserveN reserves n events in the current or the next window, counting
+// them in the window they may happen in. The reservation is not ok when the
+// events do not fit in the next window either.
+func (c *SlidingWindowCounter) ReserveN(n int) *Reservation {
+	delay, start, ok := c.take(n, true)
+	return NewReservation(ok, delay, func() { c.cancel(start, n) })
+}
+
+// take counts n events if the weighted count allows them now or, if reserve
+// is set, in the current or the next window. It returns the delay until the
+// events may happen and the start of the window they are counted in.
+func (c *SlidingWindowCounter) take(n int, reserve bool) (time.Duration, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	c.advance(now)
+	if n > c.limit {
+		return 0, c.start, false
+	}
+	remaining := c.window - now.Sub(c.start)
+	if c.curr+n > c.limit {
+		if !reserve || c.next+n > c.limit {
+			return remaining, c.start, false
+		}
+		// In the next window, the current one becomes the previous one.
+		c.next += n
+		return remaining + c.overlapDelay(c.window, c.curr, c.next), c.start.Add(c.window), true
+	}
+	delay := c.overlapDelay(remaining, c.prev, c.curr+n)
+	if delay > 0 && !reserve {
+		return max(delay, time.Millisecond), c.start, false
+	}
+	c.curr += n
+	return delay, c.start, true
+}
+
+// overlapDelay returns the delay until the previous window of prev events,
+// overlapping the sliding window by remaining, weighs little enough for the
+// count of the current window to fit in the limit.
+func (c *SlidingWindowCounter) overlapDelay(remaining time.Duration, prev, count int) time.Duration {
+	weight := float64(remaining) / float64(c.window)
+	if prev == 0 || float64(prev)*weight+float64(count) <= float64(c.limit) {
+		return 0
+	}
+	overlap := float64(c.limit-count) / float64(prev)
+	return max(remaining-time.Duration(overlap*float64(c.window)), 0)
+}
+
+// cancel uncounts n events counted in the window starting at start.
+func (c *SlidingWindowCounter) cancel(start time.Time, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance(time.Now())
+	switch {
+	case start.Equal(c.start):
+		c.curr = max(c.curr-n, 0)
+	case start.Equal(c.start.Add(c.window)):
+		c.next = max(c.next-n, 0)
+	}
+}
+
+// advance moves the windows forward to now.
+func (c *SlidingWindowCounter) advance(now time.Time) {
+	elapsed := now.Sub(c.start)
+	if elapsed < c.window {
+		return
+	}
+	switch windows := elapsed / c.window; windows {
+	case 1:
+		c.prev, c.curr = c.curr, c.next
+	case 2:
+		c.prev, c.curr = c.next, 0
+	default:
+		c.prev, c.curr = 0, 0
+	}
+	c.next = 0
+	c.start = c.start.Add(elapsed / c.window * c.window)
+}
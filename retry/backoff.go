@@ -1,10 +1,95 @@
 package retry
 
 import (
+	"math"
 	"math/rand"
 	"time"
 )
 
+// Backoff computes the delay before a retry.
+type Backoff interface {
+	// Backoff returns the delay before the given retry, counted from 1.
+	Backoff(retries int) time.Duration
+}
+
+// Exponential returns a Backoff starting at base and growing by mult for
+// each retry, capped at 15 seconds.
+func Exponential(base time.Duration, mult float64) Backoff {
+	bc := defaultBackoff()
+	if base > 0 {
+		bc.baseDelay = base
+	}
+	if mult > 0 {
+		bc.mult = mult
+	}
+	return bc
+}
+
+// BackoffFunc is an adapter to use a function as a Backoff.
+type BackoffFunc func(retries int) time.Duration
+
+// Backoff calls f.
+func (f BackoffFunc) Backoff(retries int) time.Duration {
+	return f(retries)
+}
+
+// Constant returns a Backoff waiting d before every retry.
+func Constant(d time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration {
+		return d
+	})
+}
+
+// Fibonacci returns a Backoff waiting base times the Fibonacci number of
+// each retry: base, base, 2*base, 3*base, 5*base...
+func Fibonacci(base time.Duration) Backoff {
+	return BackoffFunc(func(retries int) time.Duration {
+		a, b := 0.0, 1.0
+		for ; retries > 0 && b < math.MaxInt64; retries-- {
+			a, b = b, a+b
+		}
+		return saturate(a * float64(base))
+	})
+}
+
+// DecorrelatedJitter returns the AWS-style decorrelated jitter Backoff,
+// drawing each delay between base and three times the previous one, up to
+// max. It is randomized already, so Retry adds no jitter to it unless
+// WithJitter is given.
+func DecorrelatedJitter(base, max time.Duration) Backoff {
+	return randomized(func(retries int) time.Duration {
+		// Delays are derived from each other, so replay the random walk;
+		// every delay still follows the distribution of the scheme.
+		d := float64(base)
+		for ; retries > 0 && d < float64(max); retries-- {
+			d = float64(base) + rand.Float64()*(3*d-float64(base))
+		}
+		return min(saturate(d), max)
+	})
+}
+
+// randomized is a Backoff whose delays are jittered already.
+type randomized func(retries int) time.Duration
+
+// Backoff calls f.
+func (f randomized) Backoff(retries int) time.Duration {
+	return f(retries)
+}
+
+// Cap limits the delays of b to max.
+func Cap(b Backoff, max time.Duration) Backoff {
+	return BackoffFunc(func(retries int) time.Duration {
+		return min(b.Backoff(retries), max)
+	})
+}
+
+func saturate(d float64) time.Duration {
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
 // backoffConfig stores the exponential backoff parameters.
 type backoffConfig struct {
 	baseDelay time.Duration
@@ -22,7 +107,8 @@ func defaultBackoff() backoffConfig {
 	}
 }
 
-func (bc backoffConfig) duration(retries int) time.Duration {
+// Backoff returns the exponential delay before the given retry without jitter.
+func (bc backoffConfig) Backoff(retries int) time.Duration {
 	if retries == 0 {
 		return bc.baseDelay
 	}
@@ -34,8 +120,17 @@ func (bc backoffConfig) duration(retries int) time.Duration {
 	if backoff > max {
 		backoff = max
 	}
-	// Randomize backoff delays so callers that fail together avoid lockstep retries.
-	backoff *= 1 + bc.jitter*(rand.Float64()*2-1)
+	return time.Duration(backoff)
+}
+
+func (bc backoffConfig) duration(retries int) time.Duration {
+	return jitter(bc.Backoff(retries), bc.jitter)
+}
+
+// jitter randomizes backoff delays by the given factor so callers that fail
+// together avoid lockstep retries.
+func jitter(d time.Duration, factor float64) time.Duration {
+	backoff := float64(d) * (1 + factor*(rand.Float64()*2-1))
 	if backoff < 0 {
 		return 0
 	}
@@ -0,0 +1,38 @@
+package retry
+
+import "sync"
+
+// Budget is a token bucket of retry credits shared by the callers of an
+// endpoint, capping the retries sent to it when it keeps failing. Each retry
+// withdraws a credit and each successful call deposits a fraction of one, so
+// at most about ratio retries are sent per successful call.
+type Budget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	ratio  float64
+}
+
+// NewBudget creates a Budget holding up to max retry credits, initially full,
+// refilled by ratio credits per successful call.
+func NewBudget(max int, ratio float64) *Budget {
+	return &Budget{tokens: float64(max), max: float64(max), ratio: ratio}
+}
+
+// Withdraw takes a retry credit, reporting false if the budget is exhausted.
+func (b *Budget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Deposit refills the budget after a successful call.
+func (b *Budget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.max)
+}
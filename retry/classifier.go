@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Classifier decides whether an error is retryable.
+type Classifier interface {
+	Classify(err error) bool
+}
+
+// Classify calls f.
+func (f Retryable) Classify(err error) bool {
+	return f(err)
+}
+
+// StatusCoder is implemented by errors carrying an HTTP status code.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// NetTimeout classifies network timeouts as retryable.
+var NetTimeout Classifier = Retryable(func(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+})
+
+// HTTPStatus classifies errors implementing StatusCoder with a 5xx status
+// or 429 Too Many Requests as retryable.
+var HTTPStatus Classifier = Retryable(func(err error) bool {
+	var sc StatusCoder
+	if !errors.As(err, &sc) {
+		return false
+	}
+	code := sc.StatusCode()
+	return code == http.StatusTooManyRequests || code >= 500 && code <= 599
+})
+
+// Any classifies an error as retryable if any of the classifiers does.
+func Any(cs ...Classifier) Classifier {
+	return Retryable(func(err error) bool {
+		for _, c := range cs {
+			if c.Classify(err) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// All classifies an error as retryable if all of the classifiers do.
+func All(cs ...Classifier) Classifier {
+	return Retryable(func(err error) bool {
+		for _, c := range cs {
+			if !c.Classify(err) {
+				return false
+			}
+		}
+		return len(cs) > 0
+	})
+}
+
+// Not inverts the classification of c.
+func Not(c Classifier) Classifier {
+	return Retryable(func(err error) bool {
+		return !c.Classify(err)
+	})
+}
@@ -0,0 +1,23 @@
+// Package grpcretry classifies gRPC errors for the retry package.
+package grpcretry
+
+import (
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-kratos/kit/retry"
+)
+
+// Codes classifies gRPC errors with one of the given codes as retryable,
+// by default Unavailable and ResourceExhausted.
+func Codes(cs ...codes.Code) retry.Classifier {
+	if len(cs) == 0 {
+		cs = []codes.Code{codes.Unavailable, codes.ResourceExhausted}
+	}
+	return retry.Retryable(func(err error) bool {
+		s, ok := status.FromError(err)
+		return ok && err != nil && slices.Contains(cs, s.Code())
+	})
+}
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// HedgeOption is hedge option.
+type HedgeOption func(*hedge)
+
+// WithHedgeDelay overrides the time to wait for an attempt before launching
+// the next one, 50ms by default.
+func WithHedgeDelay(d time.Duration) HedgeOption {
+	return func(h *hedge) {
+		if d > 0 {
+			h.delay = d
+		}
+	}
+}
+
+// WithMaxHedges overrides the number of attempts launched in addition to the
+// first one, 1 by default.
+func WithMaxHedges(n int) HedgeOption {
+	return func(h *hedge) {
+		if n >= 0 {
+			h.max = n
+		}
+	}
+}
+
+type hedge struct {
+	delay time.Duration
+	max   int
+}
+
+// Hedge calls fn and, if it has not completed within the hedge delay, calls it
+// again concurrently, up to the maximum number of hedges. A failed attempt
+// launches the next one immediately. The first successful result is returned
+// and the context of the other attempts is canceled; if every attempt fails,
+// the last error is returned. fn must be safe for concurrent use.
+func Hedge[T any](ctx context.Context, fn func(context.Context) (T, error), opts ...HedgeOption) (T, error) {
+	h := hedge{delay: 50 * time.Millisecond, max: 1}
+	for _, o := range opts {
+		o(&h)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+	results := make(chan result, h.max+1)
+	var launched, running int
+	launch := func() {
+		launched++
+		running++
+		go func() {
+			v, err := fn(ctx)
+			results <- result{v, err}
+		}()
+	}
+	launch()
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+
+	var (
+		zero T
+		err  error
+	)
+	for {
+		select {
+		case r := <-results:
+			running--
+			if r.err == nil {
+				return r.v, nil
+			}
+			err = r.err
+			if launched <= h.max {
+				launch()
+				timer.Reset(h.delay)
+			} else if running == 0 {
+				return zero, err
+			}
+		case <-timer.C:
+			if launched <= h.max {
+				launch()
+				timer.Reset(h.delay)
+			}
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package retry
+
+import "time"
+
+// Outcome tells how a call to Do ended.
+type Outcome string
+
+const (
+	// OutcomeSuccess means that an attempt succeeded.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeNonRetryable means that an attempt failed with a non-retryable error.
+	OutcomeNonRetryable Outcome = "non_retryable"
+	// OutcomeExhausted means that the maximum number of attempts was reached.
+	OutcomeExhausted Outcome = "exhausted"
+	// OutcomeBudgetExhausted means that the retry budget was exhausted.
+	OutcomeBudgetExhausted Outcome = "budget_exhausted"
+	// OutcomeDeadline means that the next attempt would exceed the deadline.
+	OutcomeDeadline Outcome = "deadline"
+	// OutcomeCanceled means that the context was done.
+	OutcomeCanceled Outcome = "canceled"
+)
+
+// MetricsRecorder records the calls to Do.
+type MetricsRecorder interface {
+	// Record reports a call with the number of attempts made, its outcome
+	// and the total delay waited between the attempts.
+	Record(attempts int, outcome Outcome, delay time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) Record(int, Outcome, time.Duration) {}
@@ -0,0 +1,61 @@
+// Package promretry records retry metrics with Prometheus.
+package promretry
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/go-kratos/kit/retry"
+)
+
+// Recorder is a retry.MetricsRecorder exporting Prometheus metrics labeled
+// with the outcome of the calls.
+type Recorder struct {
+	calls    *prometheus.CounterVec
+	attempts *prometheus.HistogramVec
+	delay    *prometheus.HistogramVec
+}
+
+var _ retry.MetricsRecorder = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder with metrics in the given namespace and
+// registers them with reg.
+func NewRecorder(reg prometheus.Registerer, namespace string) (*Recorder, error) {
+	r := &Recorder{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "retry",
+			Name:      "calls_total",
+			Help:      "Number of retried calls by outcome.",
+		}, []string{"outcome"}),
+		attempts: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "retry",
+			Name:      "attempts",
+			Help:      "Number of attempts per call by outcome.",
+			Buckets:   []float64{1, 2, 3, 5, 8, 13},
+		}, []string{"outcome"}),
+		delay: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "retry",
+			Name:      "delay_seconds",
+			Help:      "Total backoff delay per call by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+	}
+	for _, c := range []prometheus.Collector{r.calls, r.attempts, r.delay} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Record implements retry.MetricsRecorder.
+func (r *Recorder) Record(attempts int, outcome retry.Outcome, delay time.Duration) {
+	label := string(outcome)
+	r.calls.WithLabelValues(label).Inc()
+	r.attempts.WithLabelValues(label).Observe(float64(attempts))
+	r.delay.WithLabelValues(label).Observe(delay.Seconds())
+}
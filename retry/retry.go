@@ -2,9 +2,15 @@ package retry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// ErrDeadlineWouldExceed is returned, wrapping the last error, when the next
+// retry cannot complete before the context deadline.
+var ErrDeadlineWouldExceed = errors.New("retry: next attempt would exceed deadline")
+
 // defaultRetry is a retry configuration with the default values.
 var defaultRetry = New(2)
 
@@ -18,6 +24,88 @@ func WithRetryable(r Retryable) Option {
 	}
 }
 
+// WithClassifier sets the Classifier deciding which errors are retried,
+// like WithRetryable.
+func WithClassifier(c Classifier) Option {
+	return func(o *Retry) {
+		o.retryable = c.Classify
+	}
+}
+
+// WithMaxAttempts overrides the maximum number of attempts; a negative value
+// retries until the context is done.
+func WithMaxAttempts(n int) Option {
+	return func(o *Retry) {
+		if n != 0 {
+			o.attempts = n
+		}
+	}
+}
+
+// WithBackoff replaces the exponential backoff configured by WithBaseDelay,
+// WithMaxDelay and WithMultiplier. The jitter factor still applies, except to
+// DecorrelatedJitter unless set with WithJitter.
+func WithBackoff(b Backoff) Option {
+	return func(o *Retry) {
+		o.strategy = b
+	}
+}
+
+// WithBudget limits the retries to the credits of the shared budget b. When the
+// budget is exhausted, Do returns the last error immediately.
+func WithBudget(b *Budget) Option {
+	return func(o *Retry) {
+		o.budget = b
+	}
+}
+
+// WithAttemptTimeout bounds each attempt with its own deadline, so that a hung
+// attempt does not consume the time left for retries. The deadline of the
+// parent context still applies.
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(o *Retry) {
+		if d > 0 {
+			o.attemptTimeout = d
+		}
+	}
+}
+
+// WithOnRetry sets a hook invoked after a failed attempt, counted from 1, with
+// the delay before the next attempt, e.g. to log or rotate endpoints.
+func WithOnRetry(fn func(attempt int, delay time.Duration, err error)) Option {
+	return func(o *Retry) {
+		o.onRetry = fn
+	}
+}
+
+// WithOnGiveUp sets a hook invoked with the number of attempts made and the
+// last error when Do gives up.
+func WithOnGiveUp(fn func(attempts int, err error)) Option {
+	return func(o *Retry) {
+		o.onGiveUp = fn
+	}
+}
+
+// WithMinAttemptDuration sets the time an attempt needs at least; Do gives up
+// with ErrDeadlineWouldExceed instead of waiting when the next backoff plus d
+// does not fit before the context deadline.
+func WithMinAttemptDuration(d time.Duration) Option {
+	return func(o *Retry) {
+		if d > 0 {
+			o.minAttempt = d
+		}
+	}
+}
+
+// WithMetrics sets the MetricsRecorder the calls to Do are reported to.
+func WithMetrics(m MetricsRecorder) Option {
+	return func(o *Retry) {
+		if m != nil {
+			o.metrics = m
+		}
+	}
+}
+
 // WithBaseDelay overrides the initial wait duration.
 func WithBaseDelay(d time.Duration) Option {
 	return func(o *Retry) {
@@ -50,6 +138,7 @@ func WithJitter(j float64) Option {
 	return func(o *Retry) {
 		if j >= 0 {
 			o.backoff.jitter = j
+			o.jitterSet = true
 		}
 	}
 }
@@ -59,9 +148,17 @@ type Retryable func(err error) bool
 
 // Retry config.
 type Retry struct {
-	backoff   backoffConfig
-	retryable Retryable
-	attempts  int
+	backoff        backoffConfig
+	strategy       Backoff
+	jitterSet      bool
+	budget         *Budget
+	retryable      Retryable
+	attempts       int
+	attemptTimeout time.Duration
+	minAttempt     time.Duration
+	onRetry        func(attempt int, delay time.Duration, err error)
+	onGiveUp       func(attempts int, err error)
+	metrics        MetricsRecorder
 }
 
 // New new a retry with backoff.
@@ -70,6 +167,7 @@ func New(attempts int, opts ...Option) *Retry {
 		attempts:  attempts,
 		retryable: func(err error) bool { return true },
 		backoff:   defaultBackoff(),
+		metrics:   nopMetrics{},
 	}
 	for _, o := range opts {
 		o(r)
@@ -79,32 +177,110 @@ func New(attempts int, opts ...Option) *Retry {
 
 // Do wraps func with a backoff to retry.
 func (r *Retry) Do(ctx context.Context, fn func(context.Context) error) error {
+	attempts, delay, outcome, err := r.do(ctx, fn)
+	if err != nil && r.onGiveUp != nil {
+		r.onGiveUp(attempts, err)
+	}
+	r.metrics.Record(attempts, outcome, delay)
+	return err
+}
+
+// do runs the retry loop, returning the number of attempts, the total delay
+// between them and the outcome.
+func (r *Retry) do(ctx context.Context, fn func(context.Context) error) (int, time.Duration, Outcome, error) {
 	var (
-		err     error
-		retries int
+		attempts int
+		total    time.Duration
 	)
 	for {
-		if err = ctx.Err(); err != nil {
-			break
+		if err := ctx.Err(); err != nil {
+			return attempts, total, OutcomeCanceled, err
+		}
+		err := r.attempt(ctx, fn)
+		attempts++
+		if err == nil {
+			if r.budget != nil {
+				r.budget.Deposit()
+			}
+			return attempts, total, OutcomeSuccess, nil
+		}
+		if !r.retryable(err) {
+			return attempts, total, OutcomeNonRetryable, err
+		}
+		if r.attempts > 0 && attempts >= r.attempts {
+			return attempts, total, OutcomeExhausted, err
+		}
+		delay := r.duration(attempts)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay+r.minAttempt {
+			return attempts, total, OutcomeDeadline, fmt.Errorf("%w: %w", ErrDeadlineWouldExceed, err)
 		}
-		if err = fn(ctx); err == nil {
-			break
+		if r.budget != nil && !r.budget.Withdraw() {
+			return attempts, total, OutcomeBudgetExhausted, err
 		}
-		if err != nil && !r.retryable(err) {
-			break
+		if r.onRetry != nil {
+			r.onRetry(attempts, delay, err)
 		}
-		retries++
-		if r.attempts > 0 && retries >= r.attempts {
-			break
+		if !sleep(ctx, delay) {
+			return attempts, total, OutcomeCanceled, ctx.Err()
 		}
-		time.Sleep(r.backoff.duration(retries))
+		total += delay
 	}
-	return err
 }
 
-// Do wraps func with a backoff to retry.
-func Do(ctx context.Context, fn func(context.Context) error) error {
-	return defaultRetry.Do(ctx, fn)
+func (r *Retry) attempt(ctx context.Context, fn func(context.Context) error) error {
+	if r.attemptTimeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.attemptTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+func (r *Retry) duration(retries int) time.Duration {
+	if r.strategy == nil {
+		return r.backoff.duration(retries)
+	}
+	if _, ok := r.strategy.(randomized); ok && !r.jitterSet {
+		return r.strategy.Backoff(retries)
+	}
+	return jitter(r.strategy.Backoff(retries), r.backoff.jitter)
+}
+
+// sleep waits for d, reporting false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// Do wraps func with a backoff to retry. Without options it makes up to
+// 2 attempts with the default backoff.
+func Do(ctx context.Context, fn func(context.Context) error, opts ...Option) error {
+	if len(opts) == 0 {
+		return defaultRetry.Do(ctx, fn)
+	}
+	return New(defaultRetry.attempts, opts...).Do(ctx, fn)
+}
+
+// DoValue is like Do for functions returning a result, which is returned
+// from the successful attempt.
+func DoValue[T any](ctx context.Context, fn func(context.Context) (T, error), opts ...Option) (T, error) {
+	var v T
+	err := Do(ctx, func(ctx context.Context) error {
+		var err error
+		v, err = fn(ctx)
+		return err
+	}, opts...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
 }
 
 // Infinite wraps func with a backoff to retry.
@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"net"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -78,3 +79,295 @@ func TestBackoffOptionsApplied(t *testing.T) {
 		t.Fatalf("expected jitter %v, got %v", jitter, r.backoff.jitter)
 	}
 }
+
+func TestDoWithOptions(t *testing.T) {
+	var calls int
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return errors.New("temporary")
+	}, WithMaxAttempts(4), WithBackoff(Exponential(time.Microsecond, 2.0)), WithJitter(0.2))
+	if err == nil {
+		t.Fatalf("expected error after exhausting attempts")
+	}
+	if calls != 4 {
+		t.Fatalf("expected 4 attempts, got %d", calls)
+	}
+}
+
+func TestRetryStopsSleepingOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	err := New(-1, WithBaseDelay(time.Hour)).Do(ctx, func(context.Context) error {
+		return errors.New("temporary")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected cancellation to interrupt the backoff, took %v", elapsed)
+	}
+}
+
+func TestRetryBudgetDeadline(t *testing.T) {
+	budget := NewBudget(1, 0.5)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := New(10, WithBudget(budget), WithBaseDelay(time.Hour)).Do(ctx, func(context.Context) error {
+		return errors.New("unavailable")
+	})
+	if !errors.Is(err, ErrDeadlineWouldExceed) {
+		t.Fatalf("expected %v, got %v", ErrDeadlineWouldExceed, err)
+	}
+	if !budget.Withdraw() {
+		t.Fatalf("expected a retry abandoned for the deadline not to spend the budget")
+	}
+}
+
+func TestRetryBudgetExhausted(t *testing.T) {
+	budget := NewBudget(2, 0.5)
+	r := New(10, WithBudget(budget), WithBaseDelay(time.Microsecond), WithMaxDelay(time.Microsecond))
+
+	var calls int
+	fail := func(context.Context) error {
+		calls++
+		return errors.New("unavailable")
+	}
+	if err := r.Do(context.Background(), fail); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+
+	calls = 0
+	if err := r.Do(context.Background(), fail); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 attempt with an exhausted budget, got %d", calls)
+	}
+
+	r.Do(context.Background(), func(context.Context) error { return nil })
+	r.Do(context.Background(), func(context.Context) error { return nil })
+	if !budget.Withdraw() {
+		t.Fatalf("expected successes to refill the budget")
+	}
+}
+
+func TestRetryAttemptTimeout(t *testing.T) {
+	var calls int
+	r := New(3, WithAttemptTimeout(5*time.Millisecond), WithBaseDelay(time.Microsecond), WithMaxDelay(time.Microsecond))
+
+	err := r.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retry returned unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryHooks(t *testing.T) {
+	wantErr := errors.New("unavailable")
+	var retried []int
+	var gaveUp int
+	r := New(3,
+		WithBaseDelay(time.Microsecond),
+		WithMaxDelay(time.Microsecond),
+		WithOnRetry(func(attempt int, delay time.Duration, err error) {
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("expected %v, got %v", wantErr, err)
+			}
+			retried = append(retried, attempt)
+		}),
+		WithOnGiveUp(func(attempts int, err error) {
+			gaveUp = attempts
+		}),
+	)
+
+	r.Do(context.Background(), func(context.Context) error { return wantErr })
+	if len(retried) != 2 || retried[0] != 1 || retried[1] != 2 {
+		t.Fatalf("expected retries after attempts [1 2], got %v", retried)
+	}
+	if gaveUp != 3 {
+		t.Fatalf("expected to give up after 3 attempts, got %d", gaveUp)
+	}
+}
+
+type statusError int
+
+func (e statusError) Error() string   { return "status error" }
+func (e statusError) StatusCode() int { return int(e) }
+
+func TestClassifiers(t *testing.T) {
+	c := Any(HTTPStatus, NetTimeout)
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{statusError(503), true},
+		{statusError(429), true},
+		{statusError(404), false},
+		{&net.DNSError{IsTimeout: true}, true},
+		{errors.New("other"), false},
+	}
+	for _, tt := range tests {
+		if got := c.Classify(tt.err); got != tt.want {
+			t.Fatalf("Classify(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+	if Not(c).Classify(statusError(503)) {
+		t.Fatalf("expected Not to invert the classification")
+	}
+	if All(HTTPStatus, Not(Retryable(func(err error) bool { return errors.Is(err, statusError(503)) }))).Classify(statusError(503)) {
+		t.Fatalf("expected All to require every classifier")
+	}
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	fib := Fibonacci(time.Millisecond)
+	for i, want := range []time.Duration{1, 1, 2, 3, 5, 8} {
+		if got := fib.Backoff(i + 1); got != want*time.Millisecond {
+			t.Fatalf("expected delay %v before retry %d, got %v", want*time.Millisecond, i+1, got)
+		}
+	}
+	if got := Cap(fib, 4*time.Millisecond).Backoff(6); got != 4*time.Millisecond {
+		t.Fatalf("expected capped delay 4ms, got %v", got)
+	}
+	if got := Constant(time.Second).Backoff(10); got != time.Second {
+		t.Fatalf("expected constant delay 1s, got %v", got)
+	}
+	if got := Fibonacci(time.Second).Backoff(1000); got <= 0 {
+		t.Fatalf("expected saturated delay, got %v", got)
+	}
+
+	dj := DecorrelatedJitter(10*time.Millisecond, time.Second)
+	for retries := 1; retries < 50; retries++ {
+		if got := dj.Backoff(retries); got < 10*time.Millisecond || got > time.Second {
+			t.Fatalf("expected delay between 10ms and 1s, got %v", got)
+		}
+	}
+}
+
+func TestDecorrelatedJitterNoExtraJitter(t *testing.T) {
+	r := New(3, WithBackoff(DecorrelatedJitter(10*time.Millisecond, 10*time.Millisecond)))
+	for retries := 1; retries < 20; retries++ {
+		if got := r.duration(retries); got != 10*time.Millisecond {
+			t.Fatalf("expected %v, got %v", 10*time.Millisecond, got)
+		}
+	}
+	r = New(3, WithBackoff(DecorrelatedJitter(10*time.Millisecond, 10*time.Millisecond)), WithJitter(0.5))
+	for retries := 1; retries < 20; retries++ {
+		if got := r.duration(retries); got < 5*time.Millisecond || got > 15*time.Millisecond {
+			t.Fatalf("expected an explicit jitter to apply, got %v", got)
+		}
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	var calls int
+	v, err := DoValue(context.Background(), func(context.Context) (string, error) {
+		calls++
+		if calls < 2 {
+			return "partial", errors.New("temporary")
+		}
+		return "ok", nil
+	}, WithBaseDelay(time.Microsecond))
+	if err != nil || v != "ok" {
+		t.Fatalf("expected ok, got %q (%v)", v, err)
+	}
+
+	v, err = DoValue(context.Background(), func(context.Context) (string, error) {
+		return "partial", errors.New("temporary")
+	}, WithMaxAttempts(1))
+	if err == nil || v != "" {
+		t.Fatalf("expected zero value with error, got %q (%v)", v, err)
+	}
+}
+
+func TestHedgeReturnsFirstSuccess(t *testing.T) {
+	var calls int32
+	v, err := Hedge(context.Background(), func(ctx context.Context) (int, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return int(n), nil
+	}, WithHedgeDelay(time.Millisecond), WithMaxHedges(2))
+	if err != nil || v != 2 {
+		t.Fatalf("expected result of the hedged attempt 2, got %d (%v)", v, err)
+	}
+}
+
+func TestHedgeAllFail(t *testing.T) {
+	wantErr := errors.New("unavailable")
+	var calls int32
+	_, err := Hedge(context.Background(), func(context.Context) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return 0, wantErr
+	}, WithHedgeDelay(time.Hour), WithMaxHedges(2))
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryDeadlineWouldExceed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	wantErr := errors.New("temporary")
+	var calls int
+	start := time.Now()
+	err := New(5, WithBaseDelay(10*time.Second), WithJitter(0)).Do(ctx, func(context.Context) error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, ErrDeadlineWouldExceed) || !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v wrapping %v, got %v", ErrDeadlineWouldExceed, wantErr, err)
+	}
+	if calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected to give up without waiting, got %d attempts in %v", calls, time.Since(start))
+	}
+
+	err = New(5, WithBaseDelay(time.Millisecond), WithMinAttemptDuration(time.Hour)).Do(ctx, func(context.Context) error {
+		return wantErr
+	})
+	if !errors.Is(err, ErrDeadlineWouldExceed) {
+		t.Fatalf("expected %v, got %v", ErrDeadlineWouldExceed, err)
+	}
+}
+
+type recorder struct {
+	attempts int
+	outcome  Outcome
+	delay    time.Duration
+}
+
+func (r *recorder) Record(attempts int, outcome Outcome, delay time.Duration) {
+	r.attempts, r.outcome, r.delay = attempts, outcome, delay
+}
+
+func TestRetryMetrics(t *testing.T) {
+	m := &recorder{}
+	r := New(3, WithMetrics(m), WithBaseDelay(time.Millisecond), WithMaxDelay(time.Millisecond), WithJitter(0))
+
+	r.Do(context.Background(), func(context.Context) error { return errors.New("temporary") })
+	if m.attempts != 3 || m.outcome != OutcomeExhausted || m.delay != 2*time.Millisecond {
+		t.Fatalf("expected 3 attempts exhausted after 2ms, got %+v", *m)
+	}
+	r.Do(context.Background(), func(context.Context) error { return nil })
+	if m.attempts != 1 || m.outcome != OutcomeSuccess || m.delay != 0 {
+		t.Fatalf("expected 1 successful attempt, got %+v", *m)
+	}
+}
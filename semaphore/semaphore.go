@@ -0,0 +1,125 @@
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrTooLarge is returned by Acquire when n exceeds the capacity.
+var ErrTooLarge = errors.New("semaphore: acquire exceeds capacity")
+
+// Weighted is a semaphore shared by work items of different weights. Waiters
+// are served in FIFO order: a large item at the head of the queue is not
+// starved by smaller ones arriving after it.
+type Weighted struct {
+	size    int64
+	mu      sync.Mutex
+	cur     int64
+	waiters list.List
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// New creates a Weighted semaphore with a capacity of size.
+func New(size int64) *Weighted {
+	return &Weighted{size: size}
+}
+
+// Acquire acquires n from the semaphore, blocking until it is available or
+// ctx is done. It fails at once with ErrTooLarge if n exceeds the capacity.
+func (s *Weighted) Acquire(ctx context.Context, n int64) error {
+	done := ctx.Done()
+	s.mu.Lock()
+	select {
+	case <-done:
+		s.mu.Unlock()
+		return ctx.Err()
+	default:
+	}
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		s.mu.Unlock()
+		return ErrTooLarge
+	}
+	ready := make(chan struct{})
+	e := s.waiters.PushBack(waiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-done:
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// Acquired after ctx was done: keep it, as if ctx were done later.
+			s.mu.Unlock()
+			return nil
+		default:
+		}
+		isFront := s.waiters.Front() == e
+		s.waiters.Remove(e)
+		// Waiters behind a removed head may fit now.
+		if isFront && s.size > s.cur {
+			s.notify()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	case <-ready:
+		return nil
+	}
+}
+
+// TryAcquire acquires n from the semaphore without blocking, reporting
+// whether it did.
+func (s *Weighted) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release releases n to the semaphore, waking the waiters that fit in order.
+func (s *Weighted) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: released more than held")
+	}
+	s.notify()
+}
+
+// Waiters returns the number of callers blocked in Acquire.
+func (s *Weighted) Waiters() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiters.Len()
+}
+
+// notify wakes the waiters from the head of the queue while they fit.
+func (s *Weighted) notify() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
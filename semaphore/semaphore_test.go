@@ -0,0 +1,86 @@
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWeighted(t *testing.T) {
+	s := New(10)
+	if err := s.Acquire(context.Background(), 6); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if s.TryAcquire(5) {
+		t.Fatalf("expected TryAcquire to fail beyond the capacity")
+	}
+	if !s.TryAcquire(4) {
+		t.Fatalf("expected TryAcquire to succeed")
+	}
+	s.Release(10)
+	if !s.TryAcquire(10) {
+		t.Fatalf("expected the capacity to be released")
+	}
+}
+
+func TestWeightedFIFO(t *testing.T) {
+	s := New(10)
+	s.Acquire(context.Background(), 5)
+	large := make(chan struct{})
+	go func() {
+		s.Acquire(context.Background(), 10)
+		close(large)
+	}()
+	for s.Waiters() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	// A small item fitting now waits behind the large one.
+	if s.TryAcquire(1) {
+		t.Fatalf("expected TryAcquire not to overtake waiters")
+	}
+	small := make(chan struct{})
+	go func() {
+		s.Acquire(context.Background(), 1)
+		close(small)
+	}()
+	for s.Waiters() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	s.Release(5)
+	<-large
+	select {
+	case <-small:
+		t.Fatalf("expected the small item to wait for the large one")
+	default:
+	}
+	s.Release(10)
+	<-small
+}
+
+func TestWeightedAcquireCanceled(t *testing.T) {
+	s := New(1)
+	s.Acquire(context.Background(), 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if s.Waiters() != 0 {
+		t.Fatalf("expected the waiter to be removed, got %v", s.Waiters())
+	}
+	s.Release(1)
+	if !s.TryAcquire(1) {
+		t.Fatalf("expected the capacity to be available")
+	}
+}
+
+func TestWeightedAcquireTooLarge(t *testing.T) {
+	s := New(2)
+	if err := s.Acquire(context.Background(), 3); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected %v, got %v", ErrTooLarge, err)
+	}
+	if s.Waiters() != 0 {
+		t.Fatalf("expected no waiter, got %v", s.Waiters())
+	}
+}
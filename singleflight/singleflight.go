@@ -0,0 +1,142 @@
+package singleflight
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Option is group option.
+type Option func(*options)
+
+type options struct {
+	ttl time.Duration
+}
+
+// WithResultTTL keeps successful results for ttl after their call completes,
+// returning them to the callers of Do in the meantime instead of calling
+// again. It prevents storms of calls on extremely hot keys, right after each
+// call completes. Results are not kept by default.
+func WithResultTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// Group deduplicates concurrent calls for the same key.
+type Group[K comparable, V any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	calls   map[K]*call[V]
+	results map[K]*call[V]
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	dups  int
+}
+
+// New creates a Group.
+func New[K comparable, V any](opts ...Option) *Group[K, V] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Group[K, V]{
+		ttl:     o.ttl,
+		calls:   make(map[K]*call[V]),
+		results: make(map[K]*call[V]),
+	}
+}
+
+// Do calls fn and returns its results, unless a call for key is in flight,
+// whose results are waited for and returned instead, or a successful result
+// of key is kept. shared reports whether the results were returned to other
+// callers as well or were kept. If fn panics, the panic propagates to the
+// caller and the other callers fail with an error.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.results[key]; ok {
+		g.mu.Unlock()
+		return c.value, nil, true
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		<-c.done
+		return c.value, c.err, true
+	}
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	g.call(key, c, fn)
+	return c.value, c.err, c.dups > 0
+}
+
+func (g *Group[K, V]) call(key K, c *call[V], fn func() (V, error)) {
+	completed := false
+	defer func() {
+		if !completed {
+			c.err = fmt.Errorf("singleflight: panic: %v", recover())
+		}
+		g.mu.Lock()
+		// A call forgotten while in flight does not keep its result.
+		if g.calls[key] == c {
+			delete(g.calls, key)
+			if completed && c.err == nil && g.ttl > 0 {
+				g.results[key] = c
+				time.AfterFunc(g.ttl, func() { g.expire(key, c) })
+			}
+		}
+		g.mu.Unlock()
+		close(c.done)
+		if !completed {
+			panic(c.err)
+		}
+	}()
+	c.value, c.err = fn()
+	completed = true
+}
+
+func (g *Group[K, V]) expire(key K, c *call[V]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.results[key] == c {
+		delete(g.results, key)
+	}
+}
+
+// Forget drops the kept result of key and makes the next Do call fn again,
+// even if a call for key is in flight.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+	delete(g.results, key)
+}
+
+// InFlight returns the keys of the calls in flight.
+func (g *Group[K, V]) InFlight() []K {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	keys := make([]K, 0, len(g.calls))
+	for k := range g.calls {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Waiting returns the number of callers waiting for the call of key in
+// flight, not counting the caller running it.
+func (g *Group[K, V]) Waiting(key K) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.calls[key]; ok {
+		return c.dups
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoDeduplicates(t *testing.T) {
+	g := New[string, int]()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, _ := g.Do("k", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 1, nil
+			})
+			if err != nil || v != 1 {
+				t.Errorf("expected %v, got %v, %v", 1, v, err)
+			}
+		}()
+	}
+	for g.Waiting("k") != 9 {
+		time.Sleep(time.Millisecond)
+	}
+	if keys := g.InFlight(); len(keys) != 1 || keys[0] != "k" {
+		t.Fatalf("expected %v, got %v", []string{"k"}, keys)
+	}
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("expected %v call, got %v", 1, calls.Load())
+	}
+	if len(g.InFlight()) != 0 {
+		t.Fatalf("expected no call in flight")
+	}
+}
+
+func TestDoResultTTL(t *testing.T) {
+	g := New[string, int](WithResultTTL(20 * time.Millisecond))
+	var calls atomic.Int32
+	fn := func() (int, error) { return int(calls.Add(1)), nil }
+	g.Do("k", fn)
+	if v, _, shared := g.Do("k", fn); v != 1 || !shared {
+		t.Fatalf("expected the kept result %v, got %v", 1, v)
+	}
+	g.Forget("k")
+	if v, _, _ := g.Do("k", fn); v != 2 {
+		t.Fatalf("expected %v, got %v", 2, v)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if v, _, _ := g.Do("k", fn); v != 3 {
+		t.Fatalf("expected the result to expire, got %v", v)
+	}
+
+	errCall := errors.New("failed")
+	g.Do("e", func() (int, error) { return 0, errCall })
+	if _, err, _ := g.Do("e", func() (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("expected errors not to be kept, got %v", err)
+	}
+}
+
+func TestForgetInFlight(t *testing.T) {
+	g := New[string, int](WithResultTTL(time.Minute))
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.Do("k", func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+	}()
+	<-started
+	g.Forget("k")
+	close(release)
+	<-done
+	if v, _, _ := g.Do("k", func() (int, error) { return 2, nil }); v != 2 {
+		t.Fatalf("expected the forgotten result not to be kept, got %v", v)
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	g := New[string, int]()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected the panic to propagate")
+			}
+		}()
+		g.Do("k", func() (int, error) { panic("boom") })
+	}()
+	if v, err, _ := g.Do("k", func() (int, error) { return 1, nil }); err != nil || v != 1 {
+		t.Fatalf("expected %v, got %v, %v", 1, v, err)
+	}
+}
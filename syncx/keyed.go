@@ -0,0 +1,115 @@
+package syncx
+
+import "sync"
+
+// KeyedMutex is a set of mutual exclusion locks, one per key, so that work on
+// different keys, such as entity IDs, is not serialized. The lock of a key
+// only exists while it is held or waited for. The zero value is ready to use.
+type KeyedMutex[K comparable] struct {
+	locks locks[K, sync.Mutex]
+}
+
+// Lock locks key, blocking until it is available.
+func (m *KeyedMutex[K]) Lock(key K) {
+	m.locks.acquire(key).Lock()
+}
+
+// TryLock tries to lock key without blocking, reporting whether it did.
+func (m *KeyedMutex[K]) TryLock(key K) bool {
+	if m.locks.acquire(key).TryLock() {
+		return true
+	}
+	m.locks.release(key)
+	return false
+}
+
+// Unlock unlocks key. It panics if key is not locked.
+func (m *KeyedMutex[K]) Unlock(key K) {
+	m.locks.release(key).Unlock()
+}
+
+// KeyedRWMutex is a set of reader/writer locks, one per key. The zero value
+// is ready to use.
+type KeyedRWMutex[K comparable] struct {
+	locks locks[K, sync.RWMutex]
+}
+
+// Lock locks key for writing, blocking until it is available.
+func (m *KeyedRWMutex[K]) Lock(key K) {
+	m.locks.acquire(key).Lock()
+}
+
+// TryLock tries to lock key for writing without blocking, reporting whether it did.
+func (m *KeyedRWMutex[K]) TryLock(key K) bool {
+	if m.locks.acquire(key).TryLock() {
+		return true
+	}
+	m.locks.release(key)
+	return false
+}
+
+// Unlock unlocks key for writing. It panics if key is not locked.
+func (m *KeyedRWMutex[K]) Unlock(key K) {
+	m.locks.release(key).Unlock()
+}
+
+// RLock locks key for reading, blocking while it is locked for writing.
+func (m *KeyedRWMutex[K]) RLock(key K) {
+	m.locks.acquire(key).RLock()
+}
+
+// TryRLock tries to lock key for reading without blocking, reporting whether it did.
+func (m *KeyedRWMutex[K]) TryRLock(key K) bool {
+	if m.locks.acquire(key).TryRLock() {
+		return true
+	}
+	m.locks.release(key)
+	return false
+}
+
+// RUnlock undoes a single RLock of key. It panics if key is not locked.
+func (m *KeyedRWMutex[K]) RUnlock(key K) {
+	m.locks.release(key).RUnlock()
+}
+
+// locks holds the locks of the keys with their reference counts: the holders
+// and the waiters of each lock.
+type locks[K comparable, L any] struct {
+	mu sync.Mutex
+	m  map[K]*refLock[L]
+}
+
+type refLock[L any] struct {
+	lock L
+	refs int
+}
+
+// acquire returns the lock of key, referencing it.
+func (ls *locks[K, L]) acquire(key K) *L {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.m == nil {
+		ls.m = make(map[K]*refLock[L])
+	}
+	l, ok := ls.m[key]
+	if !ok {
+		l = &refLock[L]{}
+		ls.m[key] = l
+	}
+	l.refs++
+	return &l.lock
+}
+
+// release returns the lock of key, dropping a reference to it.
+func (ls *locks[K, L]) release(key K) *L {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	l, ok := ls.m[key]
+	if !ok {
+		panic("syncx: unlock of unlocked key")
+	}
+	if l.refs--; l.refs == 0 {
+		delete(ls.m, key)
+	}
+	return &l.lock
+}
@@ -0,0 +1,72 @@
+package syncx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OnceError calls a function until it succeeds once, e.g. to lazily connect a
+// client that may fail its first attempts. Unlike sync.Once, a failure is not
+// remembered. The zero value is ready to use.
+type OnceError struct {
+	mu   sync.Mutex
+	done atomic.Bool
+}
+
+// Do calls fn unless a previous call succeeded since the last Reset,
+// returning its error. Concurrent calls wait for the call in progress.
+func (o *OnceError) Do(fn func() error) error {
+	if o.done.Load() {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done.Load() {
+		return nil
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	o.done.Store(true)
+	return nil
+}
+
+// Reset makes the next Do call its function again.
+func (o *OnceError) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.done.Store(false)
+}
+
+// OnceValue computes a value until it succeeds once, caching the value. The
+// zero value is ready to use.
+type OnceValue[T any] struct {
+	mu    sync.Mutex
+	value atomic.Pointer[T]
+}
+
+// Do returns the cached value, or calls fn to compute it, caching it if fn
+// succeeds. Concurrent calls wait for the call in progress.
+func (o *OnceValue[T]) Do(fn func() (T, error)) (T, error) {
+	if v := o.value.Load(); v != nil {
+		return *v, nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if v := o.value.Load(); v != nil {
+		return *v, nil
+	}
+	v, err := fn()
+	if err != nil {
+		return v, err
+	}
+	o.value.Store(&v)
+	return v, nil
+}
+
+// Reset drops the cached value, so that the next Do computes it again.
+func (o *OnceValue[T]) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.value.Store(nil)
+}
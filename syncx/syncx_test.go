@@ -0,0 +1,173 @@
+package syncx
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var m KeyedMutex[string]
+	counts := make(map[string]int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := []string{"a", "b"}[i%2]
+			m.Lock(key)
+			defer m.Unlock(key)
+			mu.Lock()
+			n := counts[key]
+			mu.Unlock()
+			time.Sleep(time.Microsecond)
+			mu.Lock()
+			counts[key] = n + 1
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if counts["a"] != 50 || counts["b"] != 50 {
+		t.Fatalf("expected the work on each key to be serialized, got %v", counts)
+	}
+	if len(m.locks.m) != 0 {
+		t.Fatalf("expected the locks to be released, got %v", len(m.locks.m))
+	}
+}
+
+func TestKeyedMutexTryLock(t *testing.T) {
+	var m KeyedMutex[int]
+	m.Lock(1)
+	if m.TryLock(1) {
+		t.Fatalf("expected TryLock of a locked key to fail")
+	}
+	if !m.TryLock(2) {
+		t.Fatalf("expected TryLock of another key to succeed")
+	}
+	m.Unlock(1)
+	m.Unlock(2)
+	if len(m.locks.m) != 0 {
+		t.Fatalf("expected the locks to be released, got %v", len(m.locks.m))
+	}
+}
+
+func TestKeyedRWMutex(t *testing.T) {
+	var m KeyedRWMutex[int]
+	m.RLock(1)
+	if !m.TryRLock(1) {
+		t.Fatalf("expected readers to share a key")
+	}
+	if m.TryLock(1) {
+		t.Fatalf("expected a writer to wait for the readers")
+	}
+	m.RUnlock(1)
+	m.RUnlock(1)
+	if !m.TryLock(1) {
+		t.Fatalf("expected the key to be free")
+	}
+	if m.TryRLock(1) {
+		t.Fatalf("expected readers to wait for the writer")
+	}
+	m.Unlock(1)
+	if len(m.locks.m) != 0 {
+		t.Fatalf("expected the locks to be released, got %v", len(m.locks.m))
+	}
+}
+
+func TestOnceError(t *testing.T) {
+	var o OnceError
+	errFailed := errors.New("failed")
+	calls := 0
+	fn := func() error {
+		calls++
+		if calls == 1 {
+			return errFailed
+		}
+		return nil
+	}
+	if err := o.Do(fn); !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+	if err := o.Do(fn); err != nil {
+		t.Fatalf("expected the call to be retried after a failure, got %v", err)
+	}
+	o.Do(fn)
+	if calls != 2 {
+		t.Fatalf("expected %v calls, got %v", 2, calls)
+	}
+	o.Reset()
+	o.Do(fn)
+	if calls != 3 {
+		t.Fatalf("expected Reset to call again, got %v calls", calls)
+	}
+}
+
+func TestOnceValue(t *testing.T) {
+	var o OnceValue[int]
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, errors.New("failed")
+		}
+		return 42, nil
+	}
+	if _, err := o.Do(fn); err == nil {
+		t.Fatalf("expected the first call to fail")
+	}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := o.Do(fn); err != nil || v != 42 {
+				t.Errorf("expected %v, got %v, %v", 42, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 2 {
+		t.Fatalf("expected %v calls, got %v", 2, calls.Load())
+	}
+	o.Reset()
+	o.Do(fn)
+	if calls.Load() != 3 {
+		t.Fatalf("expected Reset to compute again, got %v calls", calls.Load())
+	}
+}
+
+func TestValue(t *testing.T) {
+	type config struct{ Addr string }
+	var v Value[config]
+	if v.Load() != (config{}) {
+		t.Fatalf("expected the zero value, got %v", v.Load())
+	}
+	v.Store(config{Addr: "a"})
+	if old := v.Swap(config{Addr: "b"}); old.Addr != "a" {
+		t.Fatalf("expected %v, got %v", "a", old.Addr)
+	}
+	if v.CompareAndSwap(config{Addr: "a"}, config{Addr: "c"}) {
+		t.Fatalf("expected CompareAndSwap to fail on a different value")
+	}
+	if !v.CompareAndSwap(config{Addr: "b"}, config{Addr: "c"}) || v.Load().Addr != "c" {
+		t.Fatalf("expected CompareAndSwap to succeed, got %v", v.Load())
+	}
+}
+
+func TestValueUpdate(t *testing.T) {
+	v := NewValue(0)
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.Update(func(n int) int { return n + 1 })
+		}()
+	}
+	wg.Wait()
+	if v.Load() != 100 {
+		t.Fatalf("expected %v, got %v", 100, v.Load())
+	}
+}
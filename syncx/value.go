@@ -0,0 +1,66 @@
+package syncx
+
+import "sync/atomic"
+
+// Value is a typed atomic.Value, e.g. to hot-swap a configuration without
+// type assertions. The zero value holds the zero value of T.
+type Value[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// NewValue creates a Value holding v.
+func NewValue[T any](v T) *Value[T] {
+	var val Value[T]
+	val.Store(v)
+	return &val
+}
+
+// Load returns the value.
+func (v *Value[T]) Load() T {
+	return deref(v.p.Load())
+}
+
+// Store sets the value to val.
+func (v *Value[T]) Store(val T) {
+	v.p.Store(&val)
+}
+
+// Swap sets the value to val and returns the previous value.
+func (v *Value[T]) Swap(val T) T {
+	return deref(v.p.Swap(&val))
+}
+
+// CompareAndSwap sets the value to new if it equals old, reporting whether it
+// did. Like atomic.Value, it panics if the values are not comparable.
+func (v *Value[T]) CompareAndSwap(old, new T) bool {
+	for {
+		p := v.p.Load()
+		if any(deref(p)) != any(old) {
+			return false
+		}
+		if v.p.CompareAndSwap(p, &new) {
+			return true
+		}
+	}
+}
+
+// Update sets the value to fn applied to the current value and returns it.
+// fn may be called several times when other goroutines update the value
+// concurrently, so it must not have side effects.
+func (v *Value[T]) Update(fn func(T) T) T {
+	for {
+		p := v.p.Load()
+		val := fn(deref(p))
+		if v.p.CompareAndSwap(p, &val) {
+			return val
+		}
+	}
+}
+
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}